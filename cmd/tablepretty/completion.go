@@ -0,0 +1,52 @@
+package main
+
+import (
+	"database/sql"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/spf13/cobra"
+)
+
+// flagValues returns the values offered by shell completion for flags
+// taking one of a fixed set of values. Formats are taken from the
+// registries, so that registered parsers and renderers are completed.
+var flagValues = map[string]func() []string{
+	"format":           func() []string { return append([]string{"auto"}, pkg.ParserNames()...) },
+	"from":             func() []string { return append([]string{"auto"}, pkg.ParserNames()...) },
+	"output":           pkg.RendererNames,
+	"to":               pkg.RendererNames,
+	"style":            func() []string { return []string{"ascii", "light", "heavy", "double", "rounded", "minimal"} },
+	"theme":            func() []string { return []string{"default", "zebra", "none"} },
+	"color":            func() []string { return []string{"auto", "always", "never"} },
+	"overflow":         func() []string { return []string{"truncate", "wrap", "footnote"} },
+	"ambiguous-width":  func() []string { return []string{"auto", "narrow", "wide"} },
+	"sanitize":         func() []string { return []string{"strip", "escape", "none"} },
+	"pager":            func() []string { return []string{"auto", "always", "never"} },
+	"column-order":     func() []string { return []string{"document", "alphabetical"} },
+	"header-row":       func() []string { return []string{"auto", "yes", "no"} },
+	"nested":           func() []string { return []string{"json", "list", "count", "expand"} },
+	"clipboard-format": func() []string { return []string{"tsv", "csv", "markdown", "html"} },
+	"quoting":          func() []string { return []string{"minimal", "all", "nonnumeric", "none"} },
+	"sql-dialect":      func() []string { return []string{"sqlite", "postgres", "mysql"} },
+	"kind":             func() []string { return []string{"inner", "left", "right", "outer"} },
+	"driver":           sql.Drivers,
+}
+
+// registerCompletions registers the completion of flag values for the
+// command and its subcommands.
+func registerCompletions(cmd *cobra.Command) {
+	for name, values := range flagValues {
+		if cmd.Flags().Lookup(name) == nil {
+			continue
+		}
+
+		values := values
+		cmd.RegisterFlagCompletionFunc(name, func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+			return values(), cobra.ShellCompDirectiveNoFileComp
+		})
+	}
+
+	for _, sub := range cmd.Commands() {
+		registerCompletions(sub)
+	}
+}
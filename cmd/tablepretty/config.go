@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// envPrefix is the prefix of environment variables setting flags,
+	// e.g. TABLEPRETTY_STYLE for --style.
+	envPrefix = "TABLEPRETTY_"
+	// configAnnotation marks flags set by the configuration file or the
+	// environment, which are not reported as changed by pflag.
+	configAnnotation = "tablepretty_configured"
+)
+
+// configPath returns the path of the configuration file, which is
+// $TABLEPRETTY_CONFIG or config.yaml in $XDG_CONFIG_HOME/tablepretty,
+// defaulting to ~/.config/tablepretty.
+func configPath() string {
+	if path := os.Getenv(envPrefix + "CONFIG"); path != "" {
+		return path
+	}
+
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "tablepretty", "config.yaml")
+}
+
+// loadConfig reads the configuration file, which maps flag names to
+// their defaults, e.g. "style: rounded". A missing file is no error.
+func loadConfig(path string) (map[string]interface{}, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read configuration")
+	}
+
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+
+	return config, nil
+}
+
+// applyConfig sets the flags of the command which are not given on the
+// command line to the values of the environment variables, falling back
+// to the configuration file. Keys of the file must name a flag of any
+// command.
+func applyConfig(cmd *cobra.Command) error {
+	path := configPath()
+	config, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	known := map[string]bool{}
+	collectFlags(cmd.Root(), known)
+	for key := range config {
+		if !known[key] {
+			return errors.Errorf(`unknown setting "%s" in %s`, key, path)
+		}
+	}
+
+	var setErr error
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if setErr != nil || flag.Changed || flag.Name == "help" {
+			return
+		}
+
+		env := envPrefix + strings.ToUpper(strings.ReplaceAll(flag.Name, "-", "_"))
+		if value, ok := os.LookupEnv(env); ok {
+			setErr = errors.Wrapf(setDefault(cmd.Flags(), flag, value), "invalid %s", env)
+			return
+		}
+
+		if value, ok := config[flag.Name]; ok {
+			setErr = errors.Wrapf(setDefault(cmd.Flags(), flag, value), "invalid %s in %s", flag.Name, path)
+		}
+	})
+
+	return setErr
+}
+
+// setDefault sets the flag to the value without marking it as changed,
+// so that e.g. a default delimiter does not force csv input. Lists set
+// every element of repeatable flags.
+func setDefault(fs *pflag.FlagSet, flag *pflag.Flag, value interface{}) error {
+	values := []interface{}{value}
+	if list, ok := value.([]interface{}); ok {
+		values = list
+	}
+
+	for _, v := range values {
+		if err := flag.Value.Set(fmt.Sprint(v)); err != nil {
+			return err
+		}
+	}
+
+	return fs.SetAnnotation(flag.Name, configAnnotation, []string{"true"})
+}
+
+// isSet reports whether the flag was given on the command line or set
+// by the configuration.
+func isSet(fs *pflag.FlagSet, name string) bool {
+	flag := fs.Lookup(name)
+	if flag == nil {
+		return false
+	}
+
+	_, configured := flag.Annotations[configAnnotation]
+	return flag.Changed || configured
+}
+
+// collectFlags adds the flag names of the command and its subcommands
+// to names.
+func collectFlags(cmd *cobra.Command, names map[string]bool) {
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		names[flag.Name] = true
+	})
+	for _, sub := range cmd.Commands() {
+		collectFlags(sub, names)
+	}
+}
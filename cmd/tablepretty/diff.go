@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/spf13/cobra"
+)
+
+// newDiffCommand returns the "diff" command, which compares two
+// versions of a file by a key column.
+func newDiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "diff OLD NEW",
+		Short:         "Compare two versions of a file by a key column",
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	fs := cmd.Flags()
+	inputFlags := addInputFlags(fs)
+	key := fs.String("key", "", "Key column matching the rows of both files")
+	changesOnly := fs.Bool("changes-only", false, "Only print added, removed and changed rows")
+	outputFlags := addOutputFlags(fs)
+	cmd.MarkFlagRequired("key")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runDiff(args[0], args[1], *key, *changesOnly, inputFlags, outputFlags)
+	}
+
+	return cmd
+}
+
+func runDiff(oldFile, newFile, key string, changesOnly bool, inputFlags *inputFlags, outputFlags *outputFlags) (err error) {
+	old, err := inputFlags.parseFile(oldFile)
+	if err != nil {
+		return err
+	}
+
+	current, err := inputFlags.parseFile(newFile)
+	if err != nil {
+		return err
+	}
+
+	c, err := pkg.Diff(old, current, key)
+	if err != nil {
+		return err
+	}
+
+	outputFlags.defaultHighlights = pkg.DiffHighlights
+	opts, err := outputFlags.options()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, inputFlags.nullOptions()...)
+	if changesOnly {
+		opts = append(opts, pkg.WithFilter(pkg.DiffColumn+` != ""`))
+	}
+
+	out, err := outputFlags.writer()
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	return formatError(pkg.FormatContent(c, out, opts...))
+}
@@ -0,0 +1,731 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/frjufvjn/table-pretty/pkg/objectstore"
+	"github.com/frjufvjn/table-pretty/pkg/parquet"
+	"github.com/frjufvjn/table-pretty/pkg/xlsx"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// inputFlags are the flags selecting and configuring the parser.
+type inputFlags struct {
+	fs        *pflag.FlagSet
+	format    *string
+	encoding  *string
+	delimiter *string
+	quote     *string
+	noHeader  *bool
+	headerRow *string
+	lenient   *bool
+	merge     *bool
+	strict    *bool
+	sheet     *string
+	widths    *[]int
+	tomlTable *string
+	xmlRow    *string
+	xmlAttrs  *bool
+	flatten   *bool
+	maxDepth  *int
+	nested    *string
+	order     *string
+	keyOrder  *[]string
+	null      *string
+	missing   *string
+	headers   *[]string
+	token     *string
+	timeout   *time.Duration
+	region    *string
+	profile   *string
+	clipboard *bool
+}
+
+func addInputFlags(fs *pflag.FlagSet) *inputFlags {
+	f := &inputFlags{
+		fs:        fs,
+		format:    fs.StringP("format", "f", "auto", "Format, supported values: auto, "+strings.Join(pkg.ParserNames(), ", ")),
+		encoding:  fs.String("encoding", "auto", "Character encoding of the input, e.g. utf-16, shift_jis, euc-kr or latin1, detected by default"),
+		delimiter: fs.StringP("delimiter", "d", ",", `Field delimiter for csv input, e.g. ";", "|" or "\t"`),
+		quote:     fs.String("quote", `"`, "Quote character for csv input"),
+		noHeader:  fs.Bool("no-header", false, "Treat the first csv record as data and name the columns col1, col2, ..., same as --header-row no"),
+		headerRow: fs.String("header-row", "auto", "Whether the first csv record is a header: auto (detected from the records below it), yes or no"),
+		lenient:   fs.Bool("lenient", false, "Pad short and truncate long csv records instead of failing"),
+		merge:     fs.Bool("merge-overflow", false, "Merge surplus fields of long csv records into the last column, implies --lenient"),
+		strict:    fs.Bool("strict-header", false, "Fail on csv headers naming a column more than once instead of renaming the repeated names"),
+		sheet:     fs.String("sheet", "", "Sheet to read for xlsx input, defaults to the first sheet"),
+		widths:    fs.IntSlice("widths", nil, "Column widths for fixed input, e.g. 10,5,8, inferred from blank columns by default"),
+		tomlTable: fs.String("toml-table", "", `Dotted key of the toml array of tables holding the rows, e.g. "servers", defaults to the first one`),
+		xmlRow:    fs.String("xml-row", "", "Name of the xml elements holding the rows, defaults to the children of the root"),
+		xmlAttrs:  fs.Bool("xml-attrs", false, `Add xml attributes as "@attribute" columns`),
+		flatten:   fs.Bool("flatten", false, "Flatten nested json, yaml, toml, xml and parquet values into dot-notation columns"),
+		maxDepth:  fs.Int("max-depth", 0, "Maximum number of levels to flatten, 0 flattens all levels"),
+		order:     fs.String("column-order", "document", "Order of json columns, document (as the keys first appear) or alphabetical"),
+		keyOrder:  fs.StringSlice("key-order", nil, "Keys of the first json columns, e.g. id,name, followed by the others in --column-order"),
+		nested:    fs.String("nested", "json", "Print nested values which are not flattened as json, list, count or expand them into rows"),
+		null:      fs.String("null-string", pkg.DefaultNullStrings.Null, `Text of null values in json, yaml and parquet input, e.g. "-"`),
+		missing:   fs.String("missing-string", pkg.DefaultNullStrings.Missing, "Text of keys missing in some json, yaml, toml, xml or parquet objects, defaults to --null-string"),
+		headers:   fs.StringArrayP("header", "H", nil, `Header of http(s) requests, e.g. "Accept: application/json", can be repeated`),
+		token:     fs.String("token", "", "Bearer token of http(s) requests"),
+		timeout:   fs.Duration("timeout", 30*time.Second, "Timeout of http(s) requests"),
+		region:    fs.String("region", "", "AWS region of s3:// input, defaults to the one of the AWS configuration"),
+		profile:   fs.String("profile", "", "AWS configuration profile of s3:// input"),
+		clipboard: fs.Bool("from-clipboard", false, "Read input from the clipboard, e.g. cells copied from a spreadsheet"),
+	}
+	fs.StringVar(f.format, "from", "auto", "Same as --format")
+
+	return f
+}
+
+// open opens the named file, URL or object, or stdin if the name is
+// empty or "-". For URLs and objects, it also returns the format of
+// their content type, if it is known. With --from-clipboard, the
+// clipboard is read instead. Compressed input is decompressed.
+func (f *inputFlags) open(name string) (io.ReadCloser, string, error) {
+	in, format, err := f.openRaw(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	r, err := pkg.Decompress(in, name)
+	if err != nil {
+		in.Close()
+		return nil, "", errors.Wrap(err, "failed to decompress")
+	}
+
+	return &decompressed{ReadCloser: r, in: in}, format, nil
+}
+
+// openRaw opens the input like open without decompressing it.
+func (f *inputFlags) openRaw(name string) (io.ReadCloser, string, error) {
+	if *f.clipboard {
+		if name != "" && name != "-" {
+			return nil, "", errors.Errorf("--from-clipboard cannot be combined with %s", name)
+		}
+
+		r, err := pkg.ReadClipboard()
+		if err != nil {
+			return nil, "", err
+		}
+		return io.NopCloser(r), "", nil
+	}
+
+	if objectstore.IsURL(name) {
+		store := &objectstore.Store{Region: *f.region, Profile: *f.profile}
+		in, format, err := store.Open(name)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "failed to open object")
+		}
+		return in, format, nil
+	}
+
+	if !pkg.IsURL(name) {
+		in, err := openInput(name)
+		return in, "", err
+	}
+
+	fetcher := &pkg.Fetcher{
+		Header:  http.Header{},
+		Token:   *f.token,
+		Timeout: *f.timeout,
+	}
+	for _, header := range *f.headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			return nil, "", errors.Errorf(`"%s" is not a valid header, expected "Name: value"`, header)
+		}
+		fetcher.Header.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+
+	in, format, err := fetcher.Open(name)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to fetch")
+	}
+
+	return in, format, nil
+}
+
+// decompressed closes the decompressor as well as its input.
+type decompressed struct {
+	io.ReadCloser
+	in io.Closer
+}
+
+func (d *decompressed) Close() error {
+	d.ReadCloser.Close()
+	return d.in.Close()
+}
+
+// parser returns the parser for the selected format, falling back to
+// the given format, e.g. of the Content-Type of a response, and to
+// detecting the format. The returned reader must be used in place of
+// in, as it converts the input to UTF-8 according to --encoding, and
+// detecting the format consumes the first bytes of the input.
+func (f *inputFlags) parser(in io.Reader, format string) (pkg.Parser, io.Reader, error) {
+	in, err := pkg.DecodeReader(in, *f.encoding)
+	if err != nil {
+		return nil, in, err
+	}
+
+	var parser pkg.Parser
+	switch {
+	case !strings.EqualFold(*f.format, "auto"):
+		if parser, err = pkg.NewParser(*f.format); err != nil {
+			return nil, in, err
+		}
+	case f.fs.Changed("delimiter") || f.fs.Changed("quote") || f.fs.Changed("no-header") || f.fs.Changed("header-row"):
+		parser = &pkg.CSVParser{}
+	case hasParser(format):
+		parser, _ = pkg.NewParser(format)
+	default:
+		if parser, in, err = pkg.DetectParser(in); err != nil {
+			return nil, in, errors.Wrap(err, "failed to detect format")
+		}
+	}
+
+	if err := f.configure(parser); err != nil {
+		return nil, in, err
+	}
+
+	return parser, in, nil
+}
+
+// nullStrings returns the texts of null values and missing keys, or nil
+// if neither is set.
+func (f *inputFlags) nullStrings() *pkg.NullStrings {
+	if !isSet(f.fs, "null-string") && !isSet(f.fs, "missing-string") {
+		return nil
+	}
+
+	nulls := &pkg.NullStrings{Null: *f.null, Missing: *f.null}
+	if isSet(f.fs, "missing-string") {
+		nulls.Missing = *f.missing
+	}
+
+	return nulls
+}
+
+// nullOptions returns the options passing the texts of null values and
+// missing keys to the renderer.
+func (f *inputFlags) nullOptions() []pkg.Option {
+	nulls := f.nullStrings()
+	if nulls == nil {
+		return nil
+	}
+
+	return []pkg.Option{pkg.WithNullString(nulls.Null), pkg.WithMissingString(nulls.Missing)}
+}
+
+// configure applies the flags to the parser.
+func (f *inputFlags) configure(parser pkg.Parser) error {
+	nested, err := pkg.ParseNestedMode(*f.nested)
+	if err != nil {
+		return err
+	}
+
+	switch p := parser.(type) {
+	case *pkg.CSVParser:
+		if isSet(f.fs, "delimiter") {
+			comma, err := parseDelimiter(*f.delimiter)
+			if err != nil {
+				return err
+			}
+			p.Delimiter = comma
+		}
+
+		if isSet(f.fs, "quote") {
+			quote := []rune(*f.quote)
+			if len(quote) != 1 {
+				return errors.Errorf(`"%s" is not a valid quote character`, *f.quote)
+			}
+			p.Quote = quote[0]
+		}
+
+		switch mode := strings.ToLower(*f.headerRow); {
+		case *f.noHeader || mode == "no":
+			p.NoHeader, p.AutoHeader = true, false
+		case mode == "yes":
+			p.NoHeader, p.AutoHeader = false, false
+		case mode == "auto":
+			p.AutoHeader = true
+		default:
+			return errors.Errorf(`"%s" is not a valid header mode`, *f.headerRow)
+		}
+		p.Lenient, p.MergeOverflow = *f.lenient || *f.merge, *f.merge
+		p.StrictHeader = *f.strict
+	case *xlsx.Parser:
+		p.Sheet = *f.sheet
+	case *pkg.FixedWidthParser:
+		p.Widths = *f.widths
+	case *pkg.JSONParser:
+		p.Flatten, p.MaxDepth, p.Nested, p.Nulls = *f.flatten, *f.maxDepth, nested, f.nullStrings()
+		p.KeyOrder = *f.keyOrder
+		if p.Order, err = pkg.ParseColumnOrder(*f.order); err != nil {
+			return err
+		}
+	case *pkg.YAMLParser:
+		p.Flatten, p.MaxDepth, p.Nested, p.Nulls = *f.flatten, *f.maxDepth, nested, f.nullStrings()
+	case *pkg.TOMLParser:
+		p.Table, p.Flatten, p.MaxDepth = *f.tomlTable, *f.flatten, *f.maxDepth
+		p.Nested, p.Nulls = nested, f.nullStrings()
+	case *pkg.XMLParser:
+		p.RowElement, p.Attributes = *f.xmlRow, *f.xmlAttrs
+		p.Flatten, p.MaxDepth, p.Nested, p.Nulls = *f.flatten, *f.maxDepth, nested, f.nullStrings()
+	case *parquet.Parser:
+		p.Flatten, p.MaxDepth, p.Nested, p.Nulls = *f.flatten, *f.maxDepth, nested, f.nullStrings()
+	}
+
+	return nil
+}
+
+// parseFile parses the named file or URL, or stdin if the name is "-".
+func (f *inputFlags) parseFile(name string) (pkg.Content, error) {
+	in, format, err := f.open(name)
+	if err != nil {
+		return pkg.Content{}, err
+	}
+	defer in.Close()
+
+	parser, r, err := f.parser(in, format)
+	if err != nil {
+		return pkg.Content{}, err
+	}
+
+	c, err := parser.Parse(r)
+	if err != nil {
+		return pkg.Content{}, errors.Wrapf(err, "failed to parse %s", name)
+	}
+	logWarnings(parser)
+
+	return c, nil
+}
+
+// outputFlags are the flags selecting and configuring the renderer.
+type outputFlags struct {
+	fs         *pflag.FlagSet
+	output     *string
+	maxWidth   *int
+	colWidth   *int
+	width      *int
+	footer     *[]string
+	pageSize   *int
+	pager      *string
+	overflow   *string
+	ambiguous  *string
+	sanitize   *string
+	numbers    *[]string
+	locale     *string
+	highlights *[]string
+	bars       *[]string
+	barWidth   *int
+	style      *string
+	align      *[]string
+	theme      *string
+	color      *string
+	delimiter  *string
+	quoting    *string
+	crlf       *bool
+	inferTypes *bool
+	sqlTable   *string
+	sqlDialect *string
+	booktabs   *bool
+	title      *string
+	caption    *string
+	quiet      *bool
+	countOnly  *bool
+	outputFile *string
+	pbcopy     *bool
+	clipFormat *string
+
+	// defaultHighlights and defaultBars are set by commands and precede
+	// --highlight and --bar
+	defaultHighlights []pkg.Highlight
+	defaultBars       map[string]pkg.BarStyle
+}
+
+func addOutputFlags(fs *pflag.FlagSet) *outputFlags {
+	f := &outputFlags{
+		fs:         fs,
+		pbcopy:     fs.BoolP("clipboard", "c", isTerminal(os.Stdout), "clipboard support, enabled by default if stdout is a terminal"),
+		clipFormat: fs.String("clipboard-format", "tsv", "Format copied to the clipboard: tsv, csv, markdown or html"),
+		output:     fs.StringP("output", "o", "table", "Output format, supported values: "+strings.Join(pkg.RendererNames(), ", ")),
+		maxWidth:   fs.Int("max-width", 0, "Width at which table cells are wrapped"),
+		align:      fs.StringSlice("align", nil, "Alignment of table columns, e.g. name=center,price=right (auto, left, right or center)"),
+		colWidth:   fs.Int("max-column-width", 0, "Display width at which table cell values are cut off"),
+		overflow:   fs.String("overflow", "truncate", "How values wider than --max-column-width are shown: truncate, wrap or footnote"),
+		ambiguous:  fs.String("ambiguous-width", "auto", "Width of characters like ○ or Ω: auto (wide in CJK locales), narrow or wide"),
+		sanitize:   fs.String("sanitize", "strip", `Control characters and ANSI escape sequences in table values: strip, escape (e.g. "\x1b") or none`),
+		numbers:    fs.StringArray("number-format", nil, `Format the numbers of a column, e.g. "price:$%'.2f" (%f with optional precision or %d, ' groups thousands), can be repeated`),
+		locale:     fs.String("locale", "", `Decimal mark and thousands separator of --number-format, e.g. "de" or "fr-CH"`),
+		highlights: fs.StringArray("highlight", nil, `Color the cells of rows matching an expression, e.g. 'status == "FAIL":red' or "latency > 500:bg-yellow:row", can be repeated`),
+		bars:       fs.StringSlice("bar", nil, "Draw the numbers of columns as bars next to their values, e.g. latency,load=spark (bar or spark, bar by default)"),
+		barWidth:   fs.Int("bar-width", 10, "Width of the bars of --bar"),
+		width:      fs.Int("width", 0, "Maximum width of table output, defaults to the terminal width or $COLUMNS, -1 disables fitting"),
+		footer:     fs.StringSlice("footer", nil, "Append a footer row to table output aggregating columns, e.g. price=sum,qty=avg (sum, avg, min, max, count)"),
+		pageSize:   fs.Int("page-size", 0, "Repeat the table header every n rows"),
+		pager:      fs.String("pager", "auto", "When to show output in $PAGER: auto (if it is longer than the terminal), always or never"),
+		theme:      fs.String("theme", "default", "Colors of table output: default, zebra or none"),
+		color:      fs.String("color", "auto", "When to color table output: auto (on terminals unless NO_COLOR is set), always or never"),
+		style:      fs.String("style", "ascii", "Table borders: ascii, light, heavy, double, rounded or minimal"),
+		delimiter:  fs.String("output-delimiter", ",", `Field delimiter for csv output, e.g. ";" or "\t"`),
+		quoting:    fs.String("quoting", "minimal", "Fields to quote in csv output: minimal, all, nonnumeric or none"),
+		crlf:       fs.Bool("crlf", false, `Terminate csv output records with "\r\n"`),
+		sqlTable:   fs.String("sql-table", "data", "Table name for sql output"),
+		sqlDialect: fs.String("sql-dialect", "sqlite", "Dialect of sql output: sqlite, postgres or mysql"),
+		booktabs:   fs.Bool("booktabs", false, "Use booktabs rules in latex output"),
+		inferTypes: fs.Bool("infer-types", false, "Write numeric and boolean columns as such in json and ndjson output instead of strings"),
+		title:      fs.String("title", "", "Title shown above the table, in place of the row count"),
+		caption:    fs.String("caption", "", "Caption shown below the table"),
+		quiet:      fs.Bool("quiet", false, "Print nothing but the table, without banners and row count"),
+		countOnly:  fs.Bool("count-only", false, "Print nothing but the number of rows"),
+		outputFile: fs.StringP("output-file", "w", "", "Write output to file, the format defaults to the one of its extension"),
+	}
+	fs.StringVar(f.output, "to", "table", "Same as --output")
+
+	return f
+}
+
+// renderer returns the renderer for the selected format. Unless it was
+// set explicitly, the format of the output file extension is used if it
+// can be rendered.
+func (f *outputFlags) renderer() (pkg.Renderer, error) {
+	renderer, err := pkg.NewRenderer(*f.output)
+	if err != nil {
+		return nil, err
+	}
+
+	if !f.fs.Changed("output") && !f.fs.Changed("to") && *f.outputFile != "" {
+		if format, ok := pkg.FormatByExtension(*f.outputFile); ok {
+			if r, err := pkg.NewRenderer(format); err == nil {
+				renderer = r
+			}
+		}
+	}
+
+	switch strings.ToLower(*f.ambiguous) {
+	case "auto":
+	case "narrow":
+		pkg.SetAmbiguousWidth(1)
+	case "wide":
+		pkg.SetAmbiguousWidth(2)
+	default:
+		return nil, errors.Errorf(`"%s" is not a valid ambiguous width`, *f.ambiguous)
+	}
+
+	highlights := append([]pkg.Highlight(nil), f.defaultHighlights...)
+	for _, spec := range *f.highlights {
+		h, err := pkg.ParseHighlight(spec)
+		if err != nil {
+			return nil, err
+		}
+		highlights = append(highlights, h)
+	}
+
+	switch r := renderer.(type) {
+	case *pkg.TableRenderer:
+		r.Highlights = highlights
+		r.MaxWidth = *f.maxWidth
+		r.MaxColumnWidth = *f.colWidth
+		r.Width = *f.width
+		r.PageSize = *f.pageSize
+		if !isSet(f.fs, "width") && *f.outputFile == "" {
+			r.Width = pkg.TerminalWidth(os.Stdout)
+		}
+		if len(*f.numbers) > 0 {
+			if r.NumberFormats, err = parseNumberFormats(*f.numbers); err != nil {
+				return nil, err
+			}
+		}
+		r.Locale = *f.locale
+		if r.Bars, err = parseBars(*f.bars); err != nil {
+			return nil, err
+		}
+		for name, style := range f.defaultBars {
+			if _, ok := r.Bars[name]; !ok {
+				r.Bars[name] = style
+			}
+		}
+		r.BarWidth = *f.barWidth
+		if r.Sanitize, err = pkg.ParseSanitize(*f.sanitize); err != nil {
+			return nil, err
+		}
+		if r.Overflow, err = pkg.ParseOverflow(*f.overflow); err != nil {
+			return nil, err
+		}
+		if r.Style, err = pkg.ParseStyle(*f.style); err != nil {
+			return nil, err
+		}
+		if r.Theme, err = pkg.ParseTheme(*f.theme); err != nil {
+			return nil, err
+		}
+		if r.Align, err = parseAlignments(*f.align); err != nil {
+			return nil, err
+		}
+		if len(*f.footer) > 0 {
+			if r.Footer, err = parseAggregates(*f.footer); err != nil {
+				return nil, err
+			}
+		}
+		switch strings.ToLower(*f.color) {
+		case "auto":
+		case "always":
+			r.ForceColor = true
+		case "never":
+			r.Theme, r.Highlights = nil, nil
+		default:
+			return nil, errors.Errorf(`"%s" is not a valid color mode`, *f.color)
+		}
+	case *pkg.HTMLRenderer:
+		r.Highlights = highlights
+	case *pkg.CSVRenderer:
+		if isSet(f.fs, "output-delimiter") {
+			if r.Delimiter, err = parseDelimiter(*f.delimiter); err != nil {
+				return nil, err
+			}
+		}
+		if r.Quoting, err = pkg.ParseQuoteMode(*f.quoting); err != nil {
+			return nil, err
+		}
+		r.UseCRLF = *f.crlf
+	case *pkg.JSONRenderer:
+		r.InferTypes = *f.inferTypes
+	case *pkg.NDJSONRenderer:
+		r.InferTypes = *f.inferTypes
+	case *pkg.LaTeXRenderer:
+		r.Booktabs = *f.booktabs
+	case *pkg.SQLRenderer:
+		r.Table = *f.sqlTable
+		if r.Dialect, err = pkg.ParseSQLDialect(*f.sqlDialect); err != nil {
+			return nil, err
+		}
+	case *xlsx.Renderer:
+		if *f.outputFile == "" {
+			return nil, errors.New("xlsx output requires --output-file")
+		}
+	}
+
+	return renderer, nil
+}
+
+// writer returns the output file, or stdout if none was given. Closing
+// stdout has no effect, so that it can be written to repeatedly. Output
+// to a terminal is shown in the pager according to --pager.
+func (f *outputFlags) writer() (io.WriteCloser, error) {
+	if *f.outputFile == "" {
+		switch strings.ToLower(*f.pager) {
+		case "auto":
+			if isTerminal(os.Stdout) {
+				return &pager{height: terminalHeight(os.Stdout)}, nil
+			}
+		case "always":
+			return &pager{}, nil
+		case "never":
+		default:
+			return nil, errors.Errorf(`"%s" is not a valid pager mode`, *f.pager)
+		}
+
+		return stdout{os.Stdout}, nil
+	}
+
+	out, err := os.Create(*f.outputFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create file")
+	}
+
+	return out, nil
+}
+
+// streamWriter is writer without the pager, which would hold all output
+// of --batch and --max-memory-rows in memory until it is closed.
+func (f *outputFlags) streamWriter() (io.WriteCloser, error) {
+	if *f.outputFile == "" {
+		return stdout{os.Stdout}, nil
+	}
+
+	return f.writer()
+}
+
+// closeOutput closes the output and sets *err to the error of Close,
+// e.g. the exit status of the pager or a failed write of the output
+// file, unless *err is set already.
+func closeOutput(out io.Closer, err *error) {
+	if cerr := out.Close(); *err == nil {
+		*err = cerr
+	}
+}
+
+// options returns the renderer, banner and clipboard options.
+func (f *outputFlags) options() ([]pkg.Option, error) {
+	if *f.countOnly {
+		return []pkg.Option{pkg.WithRenderer(&pkg.CountRenderer{})}, nil
+	}
+
+	renderer, err := f.renderer()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []pkg.Option{pkg.WithRenderer(renderer)}
+	if _, ok := renderer.(*pkg.TableRenderer); ok && *f.outputFile == "" {
+		opts = append(opts, pkg.WithBanner())
+	}
+	if *f.quiet {
+		opts = append(opts, pkg.WithPlainOutput())
+	}
+	if *f.title != "" {
+		opts = append(opts, pkg.WithTitle(*f.title))
+	}
+	if *f.caption != "" {
+		opts = append(opts, pkg.WithCaption(*f.caption))
+	}
+	sanitize, err := pkg.ParseSanitize(*f.sanitize)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, pkg.WithSanitize(sanitize))
+	if *f.pbcopy {
+		format, err := pkg.ParseClipboardFormat(*f.clipFormat)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, pkg.WithClipboardFormat(format))
+	}
+
+	return opts, nil
+}
+
+// openInput opens the named file, or stdin if the name is empty or "-".
+func openInput(name string) (io.ReadCloser, error) {
+	if name == "" || name == "-" {
+		return os.Stdin, nil
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open file")
+	}
+
+	return f, nil
+}
+
+// logWarnings prints the warnings collected by lenient parsers.
+func logWarnings(parser pkg.Parser) {
+	if p, ok := parser.(*pkg.CSVParser); ok {
+		for _, w := range p.Warnings {
+			log.Printf("warning: %s", w)
+		}
+	}
+}
+
+// formatError downgrades clipboard failures to a warning, as the table
+// has been printed nevertheless.
+func formatError(err error) error {
+	if errors.Is(err, pkg.ErrClipboard) {
+		log.Printf("warning: %v", err)
+		return nil
+	}
+
+	return err
+}
+
+func parseDelimiter(s string) (rune, error) {
+	switch s {
+	case `\t`, "tab":
+		return '\t', nil
+	}
+
+	r := []rune(s)
+	if len(r) != 1 {
+		return 0, errors.Errorf(`"%s" is not a valid delimiter`, s)
+	}
+
+	return r[0], nil
+}
+
+// parseNumberFormats parses column:format pairs, e.g. "price:%.2f".
+func parseNumberFormats(specs []string) (map[string]pkg.NumberFormat, error) {
+	formats := map[string]pkg.NumberFormat{}
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, ":", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf(`"%s" is not a valid number format, expected column:format`, spec)
+		}
+
+		format, err := pkg.ParseNumberFormat(parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		formats[parts[0]] = format
+	}
+
+	return formats, nil
+}
+
+// parseBars parses column=style pairs, the style defaults to bar.
+func parseBars(specs []string) (map[string]pkg.BarStyle, error) {
+	bars := map[string]pkg.BarStyle{}
+	for _, spec := range specs {
+		name, style := spec, pkg.BarHorizontal
+		if i := strings.LastIndex(spec, "="); i >= 0 {
+			var err error
+			if style, err = pkg.ParseBarStyle(spec[i+1:]); err != nil {
+				return nil, err
+			}
+			name = spec[:i]
+		}
+
+		bars[name] = style
+	}
+
+	return bars, nil
+}
+
+func parseAlignments(specs []string) (map[string]pkg.Alignment, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	align := map[string]pkg.Alignment{}
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf(`"%s" is not a valid alignment, expected column=alignment`, spec)
+		}
+
+		a, err := pkg.ParseAlignment(parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		align[parts[0]] = a
+	}
+
+	return align, nil
+}
+
+// hasParser reports whether a parser is registered for the format.
+func hasParser(format string) bool {
+	if format == "" {
+		return false
+	}
+
+	_, err := pkg.NewParser(format)
+	return err == nil
+}
+
+// stdout is os.Stdout, which is not closed.
+type stdout struct {
+	*os.File
+}
+
+func (stdout) Close() error {
+	return nil
+}
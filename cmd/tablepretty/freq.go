@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/spf13/cobra"
+)
+
+// newFreqCommand returns the "freq" command, which prints how often the
+// values of a column occur.
+func newFreqCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "freq [FILE]",
+		Short:         "Print the most frequent values of a column with their counts and percentages",
+		Args:          cobra.MaximumNArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	fs := cmd.Flags()
+	inputFlags := addInputFlags(fs)
+	by := fs.StringSlice("by", nil, "Columns whose values are counted, e.g. status or method,status")
+	top := fs.Int("top", 0, "Print only the n most frequent values")
+	outputFlags := addOutputFlags(fs)
+	cmd.MarkFlagRequired("by")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		return runFreq(name, *by, *top, inputFlags, outputFlags)
+	}
+
+	return cmd
+}
+
+func runFreq(name string, by []string, top int, inputFlags *inputFlags, outputFlags *outputFlags) (err error) {
+	c, err := inputFlags.parseFile(name)
+	if err != nil {
+		return err
+	}
+
+	c, err = pkg.Frequencies(c, by...)
+	if err != nil {
+		return err
+	}
+
+	outputFlags.defaultBars = map[string]pkg.BarStyle{"percent": pkg.BarHorizontal}
+	opts, err := outputFlags.options()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, inputFlags.nullOptions()...)
+	if top > 0 {
+		opts = append(opts, pkg.WithTransform(pkg.Limit(top)))
+	}
+
+	out, err := outputFlags.writer()
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	return formatError(pkg.FormatContent(c, out, opts...))
+}
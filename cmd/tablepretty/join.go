@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/spf13/cobra"
+)
+
+// newJoinCommand returns the "join" command, which joins two files on
+// a key column.
+func newJoinCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "join LEFT RIGHT",
+		Short:         "Join two files on a key column",
+		Args:          cobra.ExactArgs(2),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	fs := cmd.Flags()
+	inputFlags := addInputFlags(fs)
+	on := fs.String("on", "", "Key column, or left=right if the names differ")
+	kind := fs.String("kind", "inner", "Join kind, supported values: inner, left, right, outer")
+	outputFlags := addOutputFlags(fs)
+	cmd.MarkFlagRequired("on")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runJoin(args[0], args[1], *on, *kind, inputFlags, outputFlags)
+	}
+
+	return cmd
+}
+
+func runJoin(leftFile, rightFile, on, kind string, inputFlags *inputFlags, outputFlags *outputFlags) (err error) {
+	onLeft, onRight := on, on
+	if parts := strings.SplitN(on, "=", 2); len(parts) == 2 {
+		onLeft, onRight = parts[0], parts[1]
+	}
+
+	joinKind, err := pkg.ParseJoinKind(kind)
+	if err != nil {
+		return err
+	}
+
+	left, err := inputFlags.parseFile(leftFile)
+	if err != nil {
+		return err
+	}
+
+	right, err := inputFlags.parseFile(rightFile)
+	if err != nil {
+		return err
+	}
+
+	c, err := pkg.Join(left, right, onLeft, onRight, joinKind)
+	if err != nil {
+		return err
+	}
+
+	opts, err := outputFlags.options()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, inputFlags.nullOptions()...)
+
+	out, err := outputFlags.writer()
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	return formatError(pkg.FormatContent(c, out, opts...))
+}
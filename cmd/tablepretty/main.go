@@ -0,0 +1,654 @@
+package main
+
+import (
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/frjufvjn/table-pretty/pkg/objectstore"
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		log.Fatal(describeError(err))
+	}
+}
+
+// describeError appends the offending input to parse errors, marking
+// the column of the error if it is known.
+func describeError(err error) string {
+	var parseErr *pkg.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Snippet == "" {
+		return err.Error()
+	}
+
+	msg := err.Error() + "\n\t" + parseErr.Snippet
+	if parseErr.Column > 0 && parseErr.Column <= len(parseErr.Snippet)+1 {
+		prefix := parseErr.Snippet[:parseErr.Column-1]
+		msg += "\n\t" + strings.Repeat(" ", runewidth.StringWidth(prefix)) + "^"
+	}
+
+	return msg
+}
+
+// newRootCommand returns the "table" command, which prints its input
+// like "table print" if no subcommand is given.
+func newRootCommand() *cobra.Command {
+	root := newPrintCommand()
+	root.Use = "table [FILE...]"
+	root.Short = "Print csv, json, yaml and other structured data as table"
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return applyConfig(cmd)
+	}
+
+	root.AddCommand(
+		newPrintCommand(),
+		newConvertCommand(),
+		newStatsCommand(),
+		newJoinCommand(),
+		newDiffCommand(),
+		newPivotCommand(),
+		newMeltCommand(),
+		newFreqCommand(),
+		newSQLCommand(),
+		newQueryCommand(),
+		newValidateCommand(),
+		newViewCommand(),
+	)
+	registerCompletions(root)
+
+	return root
+}
+
+// newPrintCommand returns the "print" command, which prints the input
+// as table or in the format selected by --output.
+func newPrintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "print [FILE...]",
+		Short: "Print the input as table",
+		Args:  cobra.ArbitraryArgs,
+		// errors are logged by main
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	flags := addPrintFlags(cmd.Flags())
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return flags.run(args)
+	}
+
+	return cmd
+}
+
+// newConvertCommand returns the "convert" command, which is "print"
+// without banners and clipboard support, to convert files between
+// formats, e.g. "table convert --to json data.csv".
+func newConvertCommand() *cobra.Command {
+	cmd := newPrintCommand()
+	cmd.Use = "convert [FILE...]"
+	cmd.Short = "Convert the input to the format selected by --to"
+
+	fs := cmd.Flags()
+	fs.Lookup("clipboard").DefValue = "false"
+	fs.Set("clipboard", "false")
+	fs.Lookup("quiet").DefValue = "true"
+	fs.Set("quiet", "true")
+
+	return cmd
+}
+
+// printFlags are the flags of the "print" command.
+type printFlags struct {
+	fs         *pflag.FlagSet
+	input      *inputFlags
+	output     *outputFlags
+	sample     *int
+	every      *int
+	seed       *int64
+	replace    *[]string
+	mask       *[]string
+	addColumns *[]string
+	filter     *string
+	dedupe     *bool
+	dedupeBy   *[]string
+	distinct   *string
+	groupBy    *[]string
+	aggregates *[]string
+	sortBy     *string
+	top        *int
+	per        *[]string
+	offset     *int
+	limit      *int
+	tail       *int
+	columns    *[]string
+	timeFormat *string
+	timeZone   *string
+	timeCols   *[]string
+	bytes      *[]string
+	durations  *[]string
+	number     *bool
+	transpose  *bool
+	inputFile  *string
+	source     *bool
+	batch      *int
+	memRows    *int
+	watch      *bool
+	interval   *time.Duration
+}
+
+func addPrintFlags(fs *pflag.FlagSet) *printFlags {
+	f := &printFlags{fs: fs, input: addInputFlags(fs)}
+	f.sample = fs.Int("sample", 0, "Print n rows matching --filter chosen at random, in their original order")
+	f.every = fs.Int("sample-every", 0, "Print every n-th row of the input, starting with the first")
+	f.seed = fs.Int64("seed", 0, "Seed of --sample, so that the same rows are chosen on every run")
+	f.replace = fs.StringArray("replace", nil, `Replace matches of a regular expression in columns, e.g. "email:/@.*/,@redacted", all columns if none are named, can be repeated`)
+	f.mask = fs.StringSlice("mask", nil, "Hide the values of columns, e.g. ssn,card=partial,email=hash (full, partial or hash, full by default)")
+	f.addColumns = fs.StringArray("add-column", nil, `Add a column computed from the others, e.g. "total=price * qty", can be repeated`)
+	f.filter = fs.String("filter", "", `Only print rows matching the expression, e.g. "price > 10 && name != 'apple'"`)
+	f.dedupe = fs.Bool("dedupe", false, "Drop duplicate rows, keeping the first")
+	f.dedupeBy = fs.StringSlice("dedupe-by", nil, "Drop rows whose values of the given columns are duplicates, keeping the first")
+	f.distinct = fs.String("distinct", "", "Print the distinct values of a column and their counts")
+	f.groupBy = fs.StringSlice("group-by", nil, "Group rows by the given columns")
+	f.aggregates = fs.StringSlice("agg", nil, "Aggregate columns per group, e.g. price=sum,qty=avg (sum, avg, min, max, count)")
+	f.sortBy = fs.String("sort", "", `Sort rows by one or more columns, e.g. "price desc, name"`)
+	f.top = fs.Int("top", 0, `Keep the first n rows of every group of --per, e.g. the slowest 3 requests per endpoint with --sort "duration desc" --per endpoint`)
+	f.per = fs.StringSlice("per", nil, "Columns whose values form the groups of --top, all rows form one group if unset")
+	f.offset = fs.Int("offset", 0, "Skip the first n rows")
+	f.limit = fs.Int("limit", -1, "Print at most the first n rows")
+	f.tail = fs.Int("tail", -1, "Print at most the last n rows")
+	f.timeFormat = fs.String("time-format", "", `Reformat dates and times with a Go layout, e.g. "02.01.2006 15:04", or rfc3339, rfc1123, datetime, date, time, kitchen, unix or unixmilli`)
+	f.timeZone = fs.String("tz", "", `Convert dates and times to a time zone, e.g. "Europe/Berlin", "UTC" or "Local"`)
+	f.timeCols = fs.StringSlice("time-columns", nil, "Columns reformatted by --time-format and --tz, which may hold epoch seconds or milliseconds, defaults to the date and time columns")
+	f.bytes = fs.StringSlice("human-bytes", nil, "Print sizes in bytes of columns with binary units, e.g. size,used=si (iec or si, iec by default)")
+	f.durations = fs.StringSlice("human-durations", nil, "Print durations of columns like 1d2h3m, e.g. uptime,latency=ms (ns, us, ms, s, m or h, s by default)")
+	f.columns = fs.StringSlice("columns", nil, "Columns to print, in the given order, e.g. name,price")
+	f.number = fs.BoolP("number", "n", false, `Prepend a "#" column holding the row number`)
+	f.transpose = fs.Bool("transpose", false, "Swap rows and columns")
+	f.inputFile = fs.StringP("input-file", "i", "", "Read input from file, http(s), s3:// or gs:// URL, same as the FILE argument")
+	f.source = fs.Bool("source", false, "Add a "+pkg.SourceColumn+" column holding the file name of every row")
+	f.output = addOutputFlags(fs)
+	f.batch = fs.IntP("batch", "b", 0, "Stream the input and render it in tables of n rows (csv only)")
+	f.memRows = fs.Int("max-memory-rows", 0, "Hold at most n rows in memory and spill further rows to a temporary file (csv only)")
+	f.watch = fs.Bool("watch", false, "Print the input again whenever the files change, or every --interval for URLs")
+	f.interval = fs.Duration("interval", 5*time.Second, "Interval at which URLs are requested again with --watch")
+
+	return f
+}
+
+// run prints the named files, or the --input-file or stdin if no file
+// is given. The rows of multiple files are concatenated. With --watch,
+// they are printed again whenever they change.
+func (f *printFlags) run(args []string) error {
+	names, err := expandGlobs(args)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		names = []string{*f.inputFile}
+	}
+
+	if *f.input.clipboard && !isSet(f.output.fs, "clipboard") {
+		// copying the table back would replace the original selection
+		*f.output.pbcopy = false
+	}
+
+	if *f.watch {
+		if *f.batch > 0 || *f.memRows > 0 {
+			return errors.New("--watch cannot be combined with --batch or --max-memory-rows")
+		}
+		if *f.input.clipboard {
+			return errors.New("--watch cannot be combined with --from-clipboard")
+		}
+
+		// the pager would wait for input instead of the next change
+		*f.output.pager = "never"
+
+		toTerminal := *f.output.outputFile == "" && isTerminal(os.Stdout)
+		return watch(names, *f.interval, toTerminal, func() error {
+			return f.print(names)
+		})
+	}
+
+	return f.print(names)
+}
+
+// print prints the named files once.
+func (f *printFlags) print(names []string) (err error) {
+	if len(names) > 1 || *f.source {
+		return f.runFiles(names)
+	}
+
+	in, format, err := f.input.open(names[0])
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	parser, r, err := f.input.parser(in, format)
+	if err != nil {
+		return err
+	}
+
+	_, ok := parser.(pkg.StreamParser)
+	sampled := ok && f.samplesStream()
+	opts, err := f.options(sampled)
+	if err != nil {
+		return err
+	}
+
+	streaming := *f.batch > 0 || *f.memRows > 0
+	writer := f.output.writer
+	if streaming {
+		writer = f.output.streamWriter
+	}
+	out, err := writer()
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	if streaming {
+		streamParser, ok := parser.(pkg.StreamParser)
+		if !ok {
+			return errors.Errorf(`"%s" does not support streaming`, *f.input.format)
+		}
+		if err := f.checkStreaming(); err != nil {
+			return err
+		}
+
+		if *f.batch > 0 {
+			err = pkg.FormatStream(streamParser, r, out, *f.batch, opts...)
+		} else {
+			err = pkg.FormatSpilled(streamParser, r, out, *f.memRows, opts...)
+		}
+		logWarnings(parser)
+
+		return err
+	}
+
+	if sampled {
+		// only the sampled rows are held in memory
+		c, err := pkg.SampleStream(parser.(pkg.StreamParser), r, *f.sample, f.random())
+		logWarnings(parser)
+		if err != nil {
+			return err
+		}
+
+		return formatError(pkg.FormatContent(c, out, opts...))
+	}
+
+	err = pkg.Format(parser, r, out, opts...)
+	logWarnings(parser)
+
+	return formatError(err)
+}
+
+// streamFlags are the flags of print besides the input flags, which
+// --batch and --max-memory-rows support: the transformations of single
+// rows and the flags not affecting the table.
+var streamFlags = map[string]bool{
+	"filter":          true,
+	"replace":         true,
+	"mask":            true,
+	"add-column":      true,
+	"columns":         true,
+	"input-file":      true,
+	"output-file":     true,
+	"output":          true,
+	"to":              true,
+	"quiet":           true,
+	"pager":           true,
+	"batch":           true,
+	"max-memory-rows": true,
+}
+
+// checkStreaming returns an error if a flag given on the command line
+// cannot be applied to the batches of --batch and --max-memory-rows,
+// which are rendered as text tables, e.g. --sort or --output json.
+func (f *printFlags) checkStreaming() error {
+	input := map[string]bool{}
+	addInputFlags(pflag.NewFlagSet("input", pflag.ContinueOnError)).fs.VisitAll(func(flag *pflag.Flag) {
+		input[flag.Name] = true
+	})
+
+	var err error
+	f.fs.Visit(func(flag *pflag.Flag) {
+		// nothing is copied to the clipboard, as if it were disabled
+		disabled := flag.Name == "clipboard" && !*f.output.pbcopy
+		if err == nil && !input[flag.Name] && !streamFlags[flag.Name] && !disabled {
+			err = errors.Errorf("--%s cannot be combined with --batch or --max-memory-rows", flag.Name)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	renderer, err := f.output.renderer()
+	if err != nil {
+		return err
+	}
+	if _, ok := renderer.(*pkg.TableRenderer); !ok {
+		return errors.New("--batch and --max-memory-rows only print tables, not other output formats")
+	}
+
+	return nil
+}
+
+// random returns the source of --sample, which is seeded by --seed if it
+// is set.
+func (f *printFlags) random() *rand.Rand {
+	if !isSet(f.fs, "seed") {
+		return nil
+	}
+
+	return rand.New(rand.NewSource(*f.seed))
+}
+
+// runFiles prints the concatenated rows of the named files.
+func (f *printFlags) runFiles(names []string) (err error) {
+	if *f.batch > 0 || *f.memRows > 0 {
+		return errors.New("--batch and --max-memory-rows require a single input")
+	}
+
+	sources := make([]pkg.Source, len(names))
+	for i, name := range names {
+		c, err := f.input.parseFile(name)
+		if err != nil {
+			return err
+		}
+
+		if name == "" {
+			name = "-"
+		}
+		sources[i] = pkg.Source{Name: name, Content: c}
+	}
+
+	c, err := pkg.Concat(sources, *f.source)
+	if err != nil {
+		return err
+	}
+
+	opts, err := f.options(false)
+	if err != nil {
+		return err
+	}
+
+	out, err := f.output.writer()
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	return formatError(pkg.FormatContent(c, out, opts...))
+}
+
+// samplesStream reports whether --sample is applied while the input of
+// a StreamParser is read, by SampleStream, as no other transformation
+// comes before it.
+func (f *printFlags) samplesStream() bool {
+	return *f.sample > 0 && *f.every <= 0 && *f.filter == ""
+}
+
+// options returns the output options followed by the transformations,
+// without --sample if the input is sampled while it is read.
+func (f *printFlags) options(sampled bool) ([]pkg.Option, error) {
+	opts, err := f.output.options()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, f.input.nullOptions()...)
+
+	for _, spec := range *f.replace {
+		columns, pattern, replacement, err := parseReplace(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, pkg.WithReplace(columns, pattern, replacement))
+	}
+	for _, spec := range *f.addColumns {
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, errors.Errorf(`"%s" is not a valid column, expected name=expression`, spec)
+		}
+
+		opts = append(opts, pkg.WithComputedColumn(strings.TrimSpace(parts[0]), parts[1]))
+	}
+	if *f.filter != "" {
+		opts = append(opts, pkg.WithFilter(*f.filter))
+	}
+	// the sample is one of the matching rows
+	if *f.every > 0 {
+		opts = append(opts, pkg.WithTransform(pkg.SampleEvery(*f.every)))
+	}
+	if *f.sample > 0 && !sampled {
+		opts = append(opts, pkg.WithSample(*f.sample, f.random()))
+	}
+	if *f.dedupe || len(*f.dedupeBy) > 0 {
+		opts = append(opts, pkg.WithDistinct(*f.dedupeBy...))
+	}
+	if *f.distinct != "" {
+		opts = append(opts, pkg.WithDistinctValues(*f.distinct))
+	}
+	if len(*f.groupBy) > 0 {
+		aggs, err := parseAggregates(*f.aggregates)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, pkg.WithTransform(pkg.GroupBy(*f.groupBy, aggs)))
+	} else if len(*f.aggregates) > 0 {
+		return nil, errors.New("--agg requires --group-by")
+	}
+	if *f.sortBy != "" {
+		opts = append(opts, pkg.WithSort(*f.sortBy))
+	}
+	if *f.top > 0 {
+		opts = append(opts, pkg.WithTopN(*f.top, *f.per, ""))
+	} else if len(*f.per) > 0 {
+		return nil, errors.New("--per requires --top")
+	}
+	if *f.offset > 0 {
+		opts = append(opts, pkg.WithTransform(pkg.Offset(*f.offset)))
+	}
+	if *f.limit >= 0 {
+		opts = append(opts, pkg.WithTransform(pkg.Limit(*f.limit)))
+	}
+	if *f.tail >= 0 {
+		opts = append(opts, pkg.WithTransform(pkg.Tail(*f.tail)))
+	}
+	if *f.timeFormat != "" || *f.timeZone != "" || len(*f.timeCols) > 0 {
+		var loc *time.Location
+		if *f.timeZone != "" {
+			if loc, err = time.LoadLocation(*f.timeZone); err != nil {
+				return nil, errors.Wrap(err, "invalid time zone")
+			}
+		}
+
+		opts = append(opts, pkg.WithTimeFormat(*f.timeCols, *f.timeFormat, loc))
+	}
+	humanize, err := parseHumanize(*f.bytes, *f.durations)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, humanize...)
+	if len(*f.columns) > 0 {
+		opts = append(opts, pkg.WithTransform(pkg.SelectColumns(*f.columns)))
+	}
+	if len(*f.mask) > 0 {
+		// masking comes last, so that filters, sorting and computed
+		// columns see the actual values
+		modes, err := parseMasks(*f.mask)
+		if err != nil {
+			return nil, err
+		}
+		if len(*f.columns) > 0 {
+			dropMissing(modes, *f.columns)
+		}
+
+		opts = append(opts, pkg.WithMask(modes))
+	}
+	if *f.number {
+		opts = append(opts, pkg.WithRowNumbers())
+	}
+	if *f.transpose {
+		opts = append(opts, pkg.WithTranspose())
+	}
+
+	return opts, nil
+}
+
+// dropMissing removes the masks of columns which are not among the
+// selected columns, as there is nothing left to mask.
+func dropMissing(modes map[string]pkg.MaskMode, columns []string) {
+	for name := range modes {
+		found := false
+		for _, column := range columns {
+			found = found || strings.EqualFold(strings.TrimSpace(column), name)
+		}
+		if !found {
+			delete(modes, name)
+		}
+	}
+}
+
+// expandGlobs expands the glob patterns among the file names, which
+// the shell leaves alone if they are quoted. URLs are kept as they are.
+func expandGlobs(names []string) ([]string, error) {
+	var out []string
+	for _, name := range names {
+		if pkg.IsURL(name) || objectstore.IsURL(name) || !strings.ContainsAny(name, "*?[") {
+			out = append(out, name)
+			continue
+		}
+
+		matches, err := filepath.Glob(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %s", name)
+		}
+		if len(matches) == 0 {
+			return nil, errors.Errorf("no files match %s", name)
+		}
+		out = append(out, matches...)
+	}
+
+	return out, nil
+}
+
+func parseAggregates(specs []string) (map[string]pkg.AggFunc, error) {
+	aggs := map[string]pkg.AggFunc{}
+	for _, spec := range specs {
+		parts := strings.SplitN(spec, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf(`"%s" is not a valid aggregate, expected column=function`, spec)
+		}
+
+		fn, err := pkg.ParseAggFunc(parts[1])
+		if err != nil {
+			return nil, err
+		}
+
+		aggs[parts[0]] = fn
+	}
+
+	return aggs, nil
+}
+
+// parseMasks parses column=mode pairs, the mode defaults to full.
+func parseMasks(specs []string) (map[string]pkg.MaskMode, error) {
+	modes := map[string]pkg.MaskMode{}
+	for _, spec := range specs {
+		name, mode := spec, pkg.MaskFull
+		if i := strings.LastIndex(spec, "="); i >= 0 {
+			var err error
+			if mode, err = pkg.ParseMaskMode(spec[i+1:]); err != nil {
+				return nil, err
+			}
+			name = spec[:i]
+		}
+
+		modes[name] = mode
+	}
+
+	return modes, nil
+}
+
+// parseHumanize parses the column=unit pairs of --human-bytes and
+// --human-durations into an option per column.
+func parseHumanize(bytes, durations []string) ([]pkg.Option, error) {
+	var opts []pkg.Option
+	for _, spec := range bytes {
+		name, unit, _ := strings.Cut(spec, "=")
+		switch strings.ToLower(unit) {
+		case "", "iec":
+			opts = append(opts, pkg.WithHumanizeBytes([]string{name}, false))
+		case "si":
+			opts = append(opts, pkg.WithHumanizeBytes([]string{name}, true))
+		default:
+			return nil, errors.Errorf(`unknown byte unit "%s", supported units: iec, si`, unit)
+		}
+	}
+	for _, spec := range durations {
+		name, unit, _ := strings.Cut(spec, "=")
+		if unit == "" {
+			unit = "s"
+		}
+		d, err := time.ParseDuration("1" + unit)
+		if err != nil {
+			return nil, errors.Errorf(`unknown duration unit "%s", supported units: ns, us, ms, s, m, h`, unit)
+		}
+
+		opts = append(opts, pkg.WithHumanizeDurations([]string{name}, d))
+	}
+
+	return opts, nil
+}
+
+// parseReplace parses a replacement like "email:/@.*/,@redacted" into
+// the columns, the regular expression and the replacement. Several
+// columns are separated by commas, and the columns and the colon are
+// left out to replace in all columns. Slashes in the expression are
+// escaped as "\/".
+func parseReplace(spec string) ([]string, string, string, error) {
+	invalid := errors.Errorf(`"%s" is not a valid replacement, expected column:/pattern/,replacement`, spec)
+
+	var columns []string
+	rest := spec
+	if !strings.HasPrefix(spec, "/") {
+		i := strings.Index(spec, ":/")
+		if i < 0 {
+			return nil, "", "", invalid
+		}
+		for _, name := range strings.Split(spec[:i], ",") {
+			columns = append(columns, strings.TrimSpace(name))
+		}
+		rest = spec[i+1:]
+	}
+
+	var pattern strings.Builder
+	for i := 1; i < len(rest); i++ {
+		switch {
+		case rest[i] == '\\' && i+1 < len(rest) && rest[i+1] == '/':
+			pattern.WriteByte('/')
+			i++
+		case rest[i] == '/':
+			if !strings.HasPrefix(rest[i+1:], ",") {
+				return nil, "", "", invalid
+			}
+			return columns, pattern.String(), rest[i+2:], nil
+		default:
+			pattern.WriteByte(rest[i])
+		}
+	}
+
+	return nil, "", "", invalid
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runTable runs the table command with the arguments and returns what
+// it writes to the csv output file.
+func runTable(t *testing.T, args ...string) string {
+	t.Helper()
+	t.Setenv(envPrefix+"CONFIG", filepath.Join(t.TempDir(), "none.yaml"))
+
+	out := filepath.Join(t.TempDir(), "out.csv")
+	root := newRootCommand()
+	root.SetArgs(append(args, "--clipboard=false", "--quiet", "--output-file", out))
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+// writeInput writes the csv input to a temporary file and returns its
+// name.
+func writeInput(t *testing.T, csv string) string {
+	t.Helper()
+
+	name := filepath.Join(t.TempDir(), "in.csv")
+	if err := os.WriteFile(name, []byte(csv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func TestPrintTransformOrder(t *testing.T) {
+	input := writeInput(t, "name,price\napple,50\npear,150\nkiwi,300\nfig,120\nplum,90\nlime,200\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "mask after sort",
+			args: []string{"--mask", "price", "--sort", "price desc", "--limit", "2"},
+			want: "name,price\nkiwi,****\nlime,****\n",
+		},
+		{
+			name: "mask after filter",
+			args: []string{"--mask", "price", "--filter", "price > 250"},
+			want: "name,price\nkiwi,****\n",
+		},
+		{
+			name: "mask after computed column",
+			args: []string{"--mask", "price", "--add-column", "double=price * 2", "--filter", "price < 60"},
+			want: "name,price,double\napple,****,100\n",
+		},
+		{
+			name: "mask of a column not selected",
+			args: []string{"--mask", "price", "--columns", "name", "--limit", "1"},
+			want: "name\napple\n",
+		},
+		{
+			name: "sample after filter",
+			args: []string{"--filter", "price > 100", "--sample", "4", "--seed", "1", "--sort", "name"},
+			want: "name,price\nfig,120\nkiwi,300\nlime,200\npear,150\n",
+		},
+		{
+			name: "sample every after filter",
+			args: []string{"--filter", "price > 100", "--sample-every", "2"},
+			want: "name,price\npear,150\nfig,120\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runTable(t, append([]string{input}, tt.args...)...); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubcommands(t *testing.T) {
+	fruits := writeInput(t, "id,name,price\n1,apple,10\n2,pear,20\n3,kiwi,30\n")
+	stock := writeInput(t, "id,name,price\n1,apple,10\n2,pear,25\n4,fig,40\n")
+	quantities := writeInput(t, "fruit,qty\n1,5\n3,2\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "convert",
+			args: []string{"convert", fruits, "--limit", "1"},
+			want: "id,name,price\n1,apple,10\n",
+		},
+		{
+			name: "join",
+			args: []string{"join", fruits, quantities, "--on", "id=fruit", "--kind", "left"},
+			want: "id,name,price,qty\n1,apple,10,5\n2,pear,20,\n3,kiwi,30,2\n",
+		},
+		{
+			name: "diff",
+			args: []string{"diff", fruits, stock, "--key", "id", "--changes-only"},
+			want: "diff,id,name,price\n~,2,pear,20 → 25\n-,3,kiwi,30\n+,4,fig,40\n",
+		},
+		{
+			name: "pivot",
+			args: []string{"pivot", fruits, "--index", "name", "--columns", "id", "--values", "price"},
+			want: "name,1,2,3\napple,10,,\npear,,20,\nkiwi,,,30\n",
+		},
+		{
+			name: "freq",
+			args: []string{"freq", stock, "--by", "price", "--top", "1"},
+			want: "price,count,percent\n10,1,33.3\n",
+		},
+		{
+			name: "query",
+			args: []string{"query", "-q", "select name from fruits where price > 15 order by price desc", "fruits=" + fruits},
+			want: "name\nkiwi\npear\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runTable(t, tt.args...); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPager(t *testing.T) {
+	t.Setenv(envPrefix+"CONFIG", filepath.Join(t.TempDir(), "none.yaml"))
+	t.Setenv("PAGER", "false")
+	in := writeInput(t, "name,qty\npear,1\nkiwi,2\n")
+
+	stdout, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+	defer func(f *os.File) { os.Stdout = f }(os.Stdout)
+	os.Stdout = stdout
+
+	// the exit status of the pager is returned
+	root := newRootCommand()
+	root.SetArgs([]string{"--clipboard=false", "--pager", "always", "--input-file", in})
+	if err := root.Execute(); err == nil {
+		t.Error("expected the error of the pager")
+	}
+
+	// batches are written as they are rendered, not to the pager
+	root = newRootCommand()
+	root.SetArgs([]string{"--clipboard=false", "--pager", "always", "--batch", "1", "--input-file", in})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "kiwi") {
+		t.Errorf("got %q, want the batches", data)
+	}
+}
+
+func TestFlagErrors(t *testing.T) {
+	t.Setenv(envPrefix+"CONFIG", filepath.Join(t.TempDir(), "none.yaml"))
+	in := writeInput(t, "name,qty\npear,1\n")
+
+	for _, args := range [][]string{
+		{"--agg", "qty=sum"},
+		{"--group-by", "name", "--agg", "name=count"},
+	} {
+		root := newRootCommand()
+		root.SetArgs(append(args, "--clipboard=false", "--input-file", in, "--output-file", filepath.Join(t.TempDir(), "out")))
+		if err := root.Execute(); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/spf13/cobra"
+)
+
+// newMeltCommand returns the "melt" command, which turns a wide table
+// into a long one.
+func newMeltCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "melt [FILE]",
+		Short:         "Turn the columns of the input into rows of id, variable and value",
+		Args:          cobra.MaximumNArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	fs := cmd.Flags()
+	inputFlags := addInputFlags(fs)
+	ids := fs.StringSlice("id", nil, "Columns kept in every row, e.g. id,name, all other columns are turned into rows")
+	variable := fs.String("var-name", "variable", "Name of the column holding the names of the columns")
+	value := fs.String("value-name", "value", "Name of the column holding the values")
+	skipEmpty := fs.Bool("skip-empty", false, "Leave out the rows of empty values")
+	outputFlags := addOutputFlags(fs)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		return runMelt(name, *ids, *variable, *value, *skipEmpty, inputFlags, outputFlags)
+	}
+
+	return cmd
+}
+
+func runMelt(name string, ids []string, variable, value string, skipEmpty bool, inputFlags *inputFlags, outputFlags *outputFlags) (err error) {
+	c, err := inputFlags.parseFile(name)
+	if err != nil {
+		return err
+	}
+
+	c, err = pkg.Melt(c, ids, variable, value)
+	if err != nil {
+		return err
+	}
+
+	opts, err := outputFlags.options()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, inputFlags.nullOptions()...)
+	if skipEmpty {
+		opts = append(opts, pkg.WithFilter("`"+value+"` != ''"))
+	}
+
+	out, err := outputFlags.writer()
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	return formatError(pkg.FormatContent(c, out, opts...))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// defaultPager is used if $PAGER is unset. The options quit if the
+// output fits on the screen, keep colors and do not clear the screen.
+var defaultPager = []string{"less", "-FRX"}
+
+// pager buffers the output and shows it in $PAGER on Close, if it has
+// more lines than the height. It is shown in the pager in any case if
+// the height is 0.
+type pager struct {
+	bytes.Buffer
+	height int
+}
+
+func (p *pager) Close() error {
+	if p.height > 0 && bytes.Count(p.Bytes(), []byte("\n")) < p.height {
+		_, err := p.WriteTo(os.Stdout)
+		return err
+	}
+
+	args := defaultPager
+	if env, ok := os.LookupEnv("PAGER"); ok {
+		args = strings.Fields(env)
+	}
+	if len(args) == 0 || args[0] == "cat" {
+		_, err := p.WriteTo(os.Stdout)
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = &p.Buffer
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		log.Printf("warning: failed to start pager: %s", err)
+		_, err := p.WriteTo(os.Stdout)
+		return err
+	}
+
+	return cmd.Wait()
+}
+
+// terminalHeight returns the height of the terminal f is, which the
+// LINES environment variable overrides. It returns 0 if the height is
+// unknown.
+func terminalHeight(f *os.File) int {
+	if n, err := strconv.Atoi(os.Getenv("LINES")); err == nil && n > 0 {
+		return n
+	}
+
+	_, height, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+
+	return height
+}
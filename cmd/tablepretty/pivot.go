@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/spf13/cobra"
+)
+
+// newPivotCommand returns the "pivot" command, which prints a cross
+// table of the input.
+func newPivotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "pivot [FILE]",
+		Short:         "Print a cross table of the input, e.g. of errors by service and day",
+		Args:          cobra.MaximumNArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	fs := cmd.Flags()
+	inputFlags := addInputFlags(fs)
+	index := fs.String("index", "", "Column whose values become the rows")
+	columns := fs.String("columns", "", "Column whose values become the columns")
+	values := fs.String("values", "", "Column aggregated in the cells, the rows are counted if it is unset")
+	agg := fs.String("agg", "sum", "Aggregate function of the cells: sum, avg, min, max or count")
+	outputFlags := addOutputFlags(fs)
+	cmd.MarkFlagRequired("index")
+	cmd.MarkFlagRequired("columns")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		return runPivot(name, *index, *columns, *values, *agg, inputFlags, outputFlags)
+	}
+
+	return cmd
+}
+
+func runPivot(name, index, columns, values, agg string, inputFlags *inputFlags, outputFlags *outputFlags) (err error) {
+	fn, err := pkg.ParseAggFunc(agg)
+	if err != nil {
+		return err
+	}
+
+	c, err := inputFlags.parseFile(name)
+	if err != nil {
+		return err
+	}
+
+	c, err = pkg.Pivot(c, index, columns, values, fn)
+	if err != nil {
+		return err
+	}
+
+	opts, err := outputFlags.options()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, inputFlags.nullOptions()...)
+
+	out, err := outputFlags.writer()
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	return formatError(pkg.FormatContent(c, out, opts...))
+}
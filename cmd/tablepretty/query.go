@@ -0,0 +1,94 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/frjufvjn/table-pretty/pkg/query"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// newQueryCommand returns the "query" command, which runs a SQL query
+// on the input files.
+func newQueryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query --query QUERY [NAME=]FILE...",
+		Short: "Run a SQL query on the input files",
+		Long: "Run a SQL query on the input files, which are loaded into an in-memory SQLite database.\n\n" +
+			"Every file is a table named after the file without its extensions, e.g. users for users.csv.gz, " +
+			"or by the name given as NAME=FILE. Stdin is the table stdin.",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	fs := cmd.Flags()
+	inputFlags := addInputFlags(fs)
+	q := fs.StringP("query", "q", "", `Query to run, e.g. "select * from users join orders using (id)"`)
+	outputFlags := addOutputFlags(fs)
+	cmd.MarkFlagRequired("query")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return runQuery(*q, args, inputFlags, outputFlags)
+	}
+
+	return cmd
+}
+
+func runQuery(q string, args []string, inputFlags *inputFlags, outputFlags *outputFlags) (err error) {
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+
+	tables := map[string]pkg.Content{}
+	for _, arg := range args {
+		name, file := tableName(arg)
+		if _, ok := tables[name]; ok {
+			return errors.Errorf(`table "%s" is given twice, name the files as NAME=FILE`, name)
+		}
+
+		if file == "-" {
+			file = ""
+		}
+		c, err := inputFlags.parseFile(file)
+		if err != nil {
+			return err
+		}
+		tables[name] = c
+	}
+
+	c, err := query.Query(q, tables)
+	if err != nil {
+		return errors.Wrap(err, "query failed")
+	}
+
+	opts, err := outputFlags.options()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, pkg.WithNullString(query.Null))
+
+	out, err := outputFlags.writer()
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	return formatError(pkg.FormatContent(c, out, opts...))
+}
+
+// tableName returns the table name and the file of a NAME=FILE
+// argument, or of a file, which is named after the file without its
+// extensions.
+func tableName(arg string) (string, string) {
+	if name, file, ok := strings.Cut(arg, "="); ok && name != "" && !strings.ContainsAny(name, `/\:`) {
+		return name, file
+	}
+	if arg == "-" {
+		return "stdin", arg
+	}
+
+	name := filepath.Base(pkg.TrimCompressionExt(arg))
+	return strings.TrimSuffix(name, filepath.Ext(name)), arg
+}
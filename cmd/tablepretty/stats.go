@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/spf13/cobra"
+)
+
+// newStatsCommand returns the "stats" command, which prints statistics
+// about every column of the input.
+func newStatsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "stats [FILE]",
+		Short:         "Print statistics about every column of the input",
+		Args:          cobra.MaximumNArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	inputFlags := addInputFlags(cmd.Flags())
+	outputFlags := addOutputFlags(cmd.Flags())
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		return runStats(name, inputFlags, outputFlags)
+	}
+
+	return cmd
+}
+
+func runStats(name string, inputFlags *inputFlags, outputFlags *outputFlags) (err error) {
+	c, err := inputFlags.parseFile(name)
+	if err != nil {
+		return err
+	}
+
+	opts, err := outputFlags.options()
+	if err != nil {
+		return err
+	}
+
+	out, err := outputFlags.writer()
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	return formatError(pkg.FormatContent(pkg.Describe(c), out, opts...))
+}
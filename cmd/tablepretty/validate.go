@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// newValidateCommand returns the "validate" command, which prints the
+// violations of a schema by the input. They are not copied to the
+// clipboard unless --clipboard is given.
+func newValidateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "validate --schema SCHEMA [FILE]",
+		Short:         "Check the columns and values against a schema and print the violations",
+		Args:          cobra.MaximumNArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	fs := cmd.Flags()
+	inputFlags := addInputFlags(fs)
+	schema := fs.String("schema", "", "The schema in YAML or JSON, listing the columns or as JSON Schema of the rows")
+	fail := fs.Bool("fail", false, "Exit with a nonzero code if there are violations")
+	outputFlags := addOutputFlags(fs)
+	fs.Lookup("clipboard").DefValue = "false"
+	fs.Set("clipboard", "false")
+	cmd.MarkFlagRequired("schema")
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		return runValidate(name, *schema, *fail, inputFlags, outputFlags)
+	}
+
+	return cmd
+}
+
+func runValidate(name, schemaPath string, fail bool, inputFlags *inputFlags, outputFlags *outputFlags) (err error) {
+	f, err := os.Open(schemaPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to open schema")
+	}
+	defer f.Close()
+
+	schema, err := pkg.ParseSchema(f)
+	if err != nil {
+		return err
+	}
+
+	c, err := inputFlags.parseFile(name)
+	if err != nil {
+		return err
+	}
+
+	violations, err := pkg.Validate(c, schema, inputFlags.nullStrings())
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	opts, err := outputFlags.options()
+	if err != nil {
+		return err
+	}
+
+	out, err := outputFlags.writer()
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	if err := formatError(pkg.FormatContent(pkg.ViolationContent(violations), out, opts...)); err != nil {
+		return err
+	}
+	if fail {
+		return errors.Errorf("violations of the schema: %d", len(violations))
+	}
+
+	return nil
+}
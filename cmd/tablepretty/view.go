@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultViewWidth is the maximum initial width of columns.
+	defaultViewWidth = 40
+	// viewHelp lists the keys of the viewer in the status line.
+	viewHelp = "q quit  / search  n/N next/previous  s sort  </> resize  enter details"
+)
+
+// newViewCommand returns the "view" command, which shows the input in
+// an interactive viewer.
+func newViewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "view [FILE]",
+		Short:         "Browse the input in an interactive viewer",
+		Args:          cobra.MaximumNArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	inputFlags := addInputFlags(cmd.Flags())
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		c, err := inputFlags.parseFile(name)
+		if err != nil {
+			return err
+		}
+
+		return newViewer(c).run()
+	}
+
+	return cmd
+}
+
+// viewer is a terminal UI to browse a Content, like less but aware of
+// rows and columns.
+type viewer struct {
+	content pkg.Content
+	widths  []int
+	sorted  string
+	query   string
+
+	app    *tview.Application
+	pages  *tview.Pages
+	table  *tview.Table
+	search *tview.InputField
+	status *tview.TextView
+}
+
+func newViewer(c pkg.Content) *viewer {
+	v := &viewer{
+		content: c,
+		widths:  make([]int, len(c.Header())),
+		app:     tview.NewApplication(),
+		pages:   tview.NewPages(),
+		table:   tview.NewTable(),
+		search:  tview.NewInputField(),
+		status:  tview.NewTextView(),
+	}
+	for j, name := range c.Header() {
+		v.widths[j] = tview.TaggedStringWidth(name)
+		for i := range c.Rows() {
+			if w := tview.TaggedStringWidth(c.At(i, j)); w > v.widths[j] {
+				v.widths[j] = w
+			}
+		}
+		if v.widths[j] > defaultViewWidth {
+			v.widths[j] = defaultViewWidth
+		}
+	}
+
+	v.table.SetFixed(1, 0).SetSelectable(true, true).SetSeparator(tview.Borders.Vertical)
+	v.table.SetSelectionChangedFunc(func(int, int) { v.updateStatus() })
+	v.table.SetSelectedFunc(func(row, _ int) { v.showDetails(row - 1) })
+	v.table.SetInputCapture(v.handleKey)
+
+	v.search.SetLabel("/").SetChangedFunc(func(query string) {
+		v.query = query
+		row, _ := v.table.GetSelection()
+		v.find(row-1, 1)
+		v.updateStatus()
+	})
+	v.search.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			v.query = ""
+		}
+		v.updateStatus()
+		v.pages.SwitchToPage("table")
+		v.app.SetFocus(v.table)
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.status, 1, 0, false)
+	searchLayout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(v.search, 1, 0, true)
+	v.pages.AddPage("table", layout, true, true)
+	v.pages.AddPage("search", searchLayout, true, false)
+
+	v.fill()
+	v.table.Select(1, 0)
+
+	return v
+}
+
+func (v *viewer) run() error {
+	return v.app.SetRoot(v.pages, true).Run()
+}
+
+// fill sets the cells of the table from the Content.
+func (v *viewer) fill() {
+	v.table.Clear()
+
+	header := v.content.Header()
+	for j, name := range header {
+		v.table.SetCell(0, j, tview.NewTableCell(tview.Escape(name)).
+			SetAttributes(tcell.AttrBold).
+			SetTextColor(tcell.ColorAqua).
+			SetSelectable(false).
+			SetMaxWidth(v.widths[j]))
+	}
+
+	for i := range v.content.Rows() {
+		for j := range header {
+			value := strings.ReplaceAll(v.content.At(i, j), "\n", " ")
+			cell := tview.NewTableCell(tview.Escape(value)).SetMaxWidth(v.widths[j])
+			if _, err := strconv.ParseFloat(value, 64); err == nil {
+				cell.SetAlign(tview.AlignRight)
+			}
+			v.table.SetCell(i+1, j, cell)
+		}
+	}
+
+	v.updateStatus()
+}
+
+func (v *viewer) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	row, column := v.table.GetSelection()
+
+	switch event.Rune() {
+	case 'q':
+		v.app.Stop()
+	case '/':
+		v.search.SetText("")
+		v.pages.ShowPage("search")
+		v.app.SetFocus(v.search)
+	case 'n':
+		v.find(row, 1)
+	case 'N':
+		v.find(row-2, -1)
+	case 's':
+		v.sort(column)
+	case '<':
+		v.resize(column, -2)
+	case '>':
+		v.resize(column, 2)
+	default:
+		if event.Key() == tcell.KeyEscape {
+			v.app.Stop()
+		}
+		return event
+	}
+
+	return nil
+}
+
+// find selects the first row containing the query, ignoring case. The
+// rows are searched in the direction, starting with the row at index
+// first and wrapping around at the end.
+func (v *viewer) find(first, direction int) {
+	rows := len(v.content.Rows())
+	if v.query == "" || rows == 0 {
+		return
+	}
+
+	query := strings.ToLower(v.query)
+	for n := 0; n < rows; n++ {
+		i := ((first+n*direction)%rows + rows) % rows
+		for j := range v.content.Header() {
+			if strings.Contains(strings.ToLower(v.content.At(i, j)), query) {
+				// the header is the first row of the table
+				_, column := v.table.GetSelection()
+				v.table.Select(i+1, column)
+				return
+			}
+		}
+	}
+}
+
+// sort sorts the rows by the column, in ascending order first and in
+// descending order if it is sorted by the column already.
+func (v *viewer) sort(column int) {
+	name := v.content.Header()[column]
+	spec := name + " asc"
+	if v.sorted == spec {
+		spec = name + " desc"
+	}
+
+	c, err := pkg.SortBy(spec)(v.content)
+	if err != nil {
+		v.status.SetText(err.Error())
+		return
+	}
+
+	v.content, v.sorted = c, spec
+	v.fill()
+	v.table.Select(1, column)
+}
+
+// resize changes the width of the column by delta. Columns do not grow
+// wider than their widest value.
+func (v *viewer) resize(column, delta int) {
+	if v.widths[column]+delta < 1 {
+		return
+	}
+
+	v.widths[column] += delta
+	for row := 0; row < v.table.GetRowCount(); row++ {
+		v.table.GetCell(row, column).SetMaxWidth(v.widths[column])
+	}
+}
+
+// showDetails shows all values of the row in a popup.
+func (v *viewer) showDetails(row int) {
+	if row < 0 || row >= len(v.content.Rows()) {
+		return
+	}
+
+	var b strings.Builder
+	c := pkg.NewContent(v.content.Header(), [][]string{v.content.Rows()[row]})
+	(&pkg.RecordRenderer{}).Render(c, &b)
+	// the title replaces the row separator of the RecordRenderer
+	_, text, _ := strings.Cut(b.String(), "\n")
+
+	details := tview.NewTextView().SetText(text).SetScrollable(true)
+	details.SetBorder(true).SetTitle(fmt.Sprintf(" row %d ", row+1))
+	details.SetDoneFunc(func(tcell.Key) {
+		v.pages.RemovePage("details")
+		v.app.SetFocus(v.table)
+	})
+
+	v.pages.AddPage("details", details, true, true)
+	v.app.SetFocus(details)
+}
+
+func (v *viewer) updateStatus() {
+	row, _ := v.table.GetSelection()
+
+	status := fmt.Sprintf("row %d/%d", row, len(v.content.Rows()))
+	if v.sorted != "" {
+		status += "  sorted by " + v.sorted
+	}
+	if v.query != "" {
+		status += "  /" + v.query
+	}
+	v.status.SetText(status + "  |  " + viewHelp)
+}
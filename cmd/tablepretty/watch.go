@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/frjufvjn/table-pretty/pkg/objectstore"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// settleDelay is waited for after a file changed, as editors and
+// exporters often write files in several steps.
+const settleDelay = 100 * time.Millisecond
+
+// watch calls render initially and whenever one of the named files
+// changes. If any of the names is a URL, render is called every
+// interval instead. The screen is cleared before rendering to a
+// terminal. Errors of render are logged and watching continues, watch
+// only returns if the files cannot be watched.
+func watch(names []string, interval time.Duration, toTerminal bool, render func() error) error {
+	redraw := func() {
+		if toTerminal {
+			// move the cursor home and clear the screen
+			fmt.Print("\x1b[H\x1b[2J")
+		}
+		if err := render(); err != nil {
+			log.Print(describeError(err))
+		}
+	}
+
+	poll := false
+	for _, name := range names {
+		switch {
+		case name == "" || name == "-":
+			return errors.New("--watch requires files or URLs, stdin cannot be watched")
+		case pkg.IsURL(name) || objectstore.IsURL(name):
+			poll = true
+		}
+	}
+
+	if poll {
+		redraw()
+		for range time.Tick(interval) {
+			redraw()
+		}
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrap(err, "failed to watch files")
+	}
+	defer watcher.Close()
+
+	// The directories are watched, as editors replace files on saving,
+	// which ends watches on the files themselves.
+	watched := map[string]bool{}
+	for _, name := range names {
+		path, err := filepath.Abs(name)
+		if err != nil {
+			return err
+		}
+		watched[path] = true
+
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			return errors.Wrapf(err, "failed to watch %s", name)
+		}
+	}
+
+	redraw()
+
+	var settle <-chan time.Time
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if watched[event.Name] && !event.Has(fsnotify.Chmod) {
+				settle = time.After(settleDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("warning: %s", err)
+		case <-settle:
+			settle = nil
+			redraw()
+		}
+	}
+}
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
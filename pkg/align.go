@@ -0,0 +1,108 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Alignment is the horizontal alignment of the values of a column.
+type Alignment int
+
+const (
+	// AlignAuto right-aligns numeric columns on the decimal point and
+	// left-aligns all others.
+	AlignAuto Alignment = iota
+	// AlignLeft left-aligns the values.
+	AlignLeft
+	// AlignRight right-aligns the values.
+	AlignRight
+	// AlignCenter centers the values.
+	AlignCenter
+)
+
+// ParseAlignment returns the alignment with the given name, i.e.
+// "auto", "left", "right" or "center".
+func ParseAlignment(name string) (Alignment, error) {
+	switch strings.ToLower(name) {
+	case "auto", "":
+		return AlignAuto, nil
+	case "left":
+		return AlignLeft, nil
+	case "right":
+		return AlignRight, nil
+	case "center", "centre":
+		return AlignCenter, nil
+	}
+
+	return AlignAuto, fmt.Errorf("unknown alignment %q", name)
+}
+
+// columnAlignments resolves the alignment of every column. Columns are
+// looked up by name, see Content.columnIndex, and default to AlignAuto.
+// AlignAuto is resolved to AlignRight for numeric columns.
+func columnAlignments(c Content, align map[string]Alignment) ([]Alignment, error) {
+	out := make([]Alignment, len(c.header))
+	for name, a := range align {
+		i := c.columnIndex(name)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		out[i] = a
+	}
+
+	for i, numeric := range numericColumns(c) {
+		if out[i] == AlignAuto {
+			out[i] = AlignLeft
+			if numeric {
+				out[i] = AlignRight
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// tablewriterAlignment converts the alignment to the tablewriter
+// constant.
+func (a Alignment) tablewriterAlignment() int {
+	switch a {
+	case AlignRight:
+		return tablewriter.ALIGN_RIGHT
+	case AlignCenter:
+		return tablewriter.ALIGN_CENTER
+	}
+
+	return tablewriter.ALIGN_LEFT
+}
+
+// alignDecimals pads the numbers of the column with trailing spaces, so
+// that their decimal marks line up when they are right-aligned, e.g.
+// "1.5 " above "10.25" and "3   ".
+func alignDecimals(rows [][]string, column int, decimal string) {
+	fraction := func(v string) int {
+		if i := strings.LastIndex(v, decimal); i >= 0 {
+			return displayWidth(v[i:])
+		}
+		return 0
+	}
+
+	width := 0
+	for _, row := range rows {
+		v := cell(row, column)
+		if strings.ContainsAny(v, "eE") {
+			// exponents can not be aligned on the decimal point
+			return
+		}
+		if f := fraction(v); f > width {
+			width = f
+		}
+	}
+
+	for _, row := range rows {
+		if column < len(row) && row[column] != "" {
+			row[column] += strings.Repeat(" ", width-fraction(row[column]))
+		}
+	}
+}
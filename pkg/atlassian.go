@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"bufio"
+	"html"
+	"io"
+	"strings"
+)
+
+var jiraEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`|`, `\|`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`{`, `\{`,
+	`}`, `\}`,
+	"\r\n", `\\ `,
+	"\n", `\\ `,
+)
+
+// JiraRenderer is a renderer implementation that emits the table syntax
+// of Jira and Confluence wiki markup, i.e. ||header|| and |cell| rows,
+// to paste results into tickets and wiki pages.
+type JiraRenderer struct{}
+
+// Render writes the Content as wiki markup table to the writer.
+func (j *JiraRenderer) Render(c Content, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("||")
+	for _, value := range c.header {
+		bw.WriteString(jiraCell(value) + "||")
+	}
+	bw.WriteString("\n")
+
+	for i := range c.rows {
+		bw.WriteString("|")
+		for j := range c.header {
+			bw.WriteString(jiraCell(c.At(i, j)) + "|")
+		}
+		bw.WriteString("\n")
+	}
+
+	return bw.Flush()
+}
+
+// jiraCell escapes the value, empty cells are replaced by a space as
+// Jira would merge them otherwise.
+func jiraCell(value string) string {
+	if value == "" {
+		return " "
+	}
+
+	return jiraEscaper.Replace(value)
+}
+
+// ConfluenceRenderer is a renderer implementation that emits a table in
+// the Confluence storage format, the XHTML representation of pages used
+// by the Confluence editor and REST API.
+type ConfluenceRenderer struct{}
+
+// Render writes the Content as Confluence storage format to the writer.
+func (cr *ConfluenceRenderer) Render(c Content, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("<table><tbody>\n<tr>")
+	for _, value := range c.header {
+		bw.WriteString("<th>" + confluenceValue(value) + "</th>")
+	}
+	bw.WriteString("</tr>\n")
+
+	for i := range c.rows {
+		bw.WriteString("<tr>")
+		for j := range c.header {
+			bw.WriteString("<td>" + confluenceValue(c.At(i, j)) + "</td>")
+		}
+		bw.WriteString("</tr>\n")
+	}
+	bw.WriteString("</tbody></table>\n")
+
+	return bw.Flush()
+}
+
+func confluenceValue(value string) string {
+	lines := strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = html.EscapeString(line)
+	}
+
+	return strings.Join(lines, "<br />")
+}
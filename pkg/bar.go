@@ -0,0 +1,143 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// defaultBarWidth is the width of the bars of BarHorizontal if the
+// TableRenderer sets no BarWidth.
+const defaultBarWidth = 10
+
+var (
+	// barEighths are the partial blocks of horizontal bars by eighths.
+	barEighths = []rune(" ▏▎▍▌▋▊▉")
+	// sparkLevels are the blocks of sparklines from lowest to highest.
+	sparkLevels = []rune("▁▂▃▄▅▆▇█")
+)
+
+// BarStyle selects how the TableRenderer draws the numbers of a column
+// next to their values, relative to the largest number of the column.
+// Negative numbers and values which are not numbers get no bar.
+type BarStyle int
+
+const (
+	// BarHorizontal draws a bar of up to BarWidth columns, e.g. "████▌".
+	BarHorizontal BarStyle = iota
+	// BarSparkline draws a single block of eight heights, e.g. "▅", so
+	// that the column reads like a sparkline.
+	BarSparkline
+)
+
+var barStyleNames = []string{"bar", "spark"}
+
+// ParseBarStyle returns the style with the given name, i.e. "bar" or
+// "spark".
+func ParseBarStyle(name string) (BarStyle, error) {
+	for i, styleName := range barStyleNames {
+		if strings.EqualFold(strings.TrimSpace(name), styleName) {
+			return BarStyle(i), nil
+		}
+	}
+
+	return BarHorizontal, fmt.Errorf("unknown bar style %q, supported styles: %s", name, strings.Join(barStyleNames, ", "))
+}
+
+func (s BarStyle) String() string {
+	if s < 0 || int(s) >= len(barStyleNames) {
+		return fmt.Sprintf("BarStyle(%d)", int(s))
+	}
+
+	return barStyleNames[s]
+}
+
+// columnBars returns the styles of the bars of the columns by index, nil
+// for columns without bars.
+func columnBars(c Content, bars map[string]BarStyle) ([]*BarStyle, error) {
+	out := make([]*BarStyle, len(c.header))
+	for name, style := range bars {
+		i := c.columnIndex(name)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		style := style
+		out[i] = &style
+	}
+
+	return out, nil
+}
+
+// addBars appends the bars of the column to the displayed rows, whose
+// values are given as they are by values. The displayed values are
+// padded to a common width first, so that the bars line up. Rows beyond
+// the values, i.e. the footer, are padded alike.
+func addBars(rows [][]string, values []string, column int, style BarStyle, width int, align Alignment) {
+	if width <= 0 {
+		width = defaultBarWidth
+	}
+	if style == BarSparkline {
+		width = 1
+	}
+
+	largest := 0.0
+	for _, value := range values {
+		if n, ok := parseNumber(value); ok && n > largest && !math.IsInf(n, 0) {
+			largest = n
+		}
+	}
+
+	// the values of right-aligned columns keep the padding of
+	// alignDecimals
+	valueWidth := 0
+	for _, row := range rows {
+		if column < len(row) {
+			valueWidth = max(valueWidth, displayWidth(row[column]))
+		}
+	}
+
+	for i, row := range rows {
+		if column >= len(row) {
+			continue
+		}
+
+		value := strings.TrimSpace(row[column])
+		if value == "" {
+			continue
+		}
+
+		bar := strings.Repeat(" ", width)
+		if i < len(values) {
+			if n, ok := parseNumber(values[i]); ok && n > 0 && largest > 0 {
+				bar = drawBar(math.Min(n/largest, 1), style, width)
+			}
+		}
+
+		switch align {
+		case AlignRight:
+			value = tablewriter.PadLeft(row[column], " ", valueWidth)
+		case AlignCenter:
+			value = tablewriter.Pad(value, " ", valueWidth)
+		default:
+			value = tablewriter.PadRight(value, " ", valueWidth)
+		}
+		row[column] = value + " " + bar
+	}
+}
+
+// drawBar draws the fraction of the width, padded with spaces.
+func drawBar(fraction float64, style BarStyle, width int) string {
+	if style == BarSparkline {
+		return string(sparkLevels[int(math.Round(fraction*float64(len(sparkLevels)-1)))])
+	}
+
+	eighths := int(math.Round(fraction * float64(width*8)))
+	bar := strings.Repeat("█", eighths/8)
+	if eighths%8 > 0 {
+		bar += string(barEighths[eighths%8])
+	}
+
+	return bar + strings.Repeat(" ", width-displayWidth(bar))
+}
@@ -0,0 +1,209 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/atotto/clipboard"
+)
+
+// ClipboardFormat selects the format WithClipboard copies the Content
+// in.
+type ClipboardFormat int
+
+const (
+	// ClipboardTSV copies tab-separated values, which spreadsheets paste
+	// into cells.
+	ClipboardTSV ClipboardFormat = iota
+	// ClipboardCSV copies comma-separated values.
+	ClipboardCSV
+	// ClipboardMarkdown copies a markdown table, e.g. for GitHub issues.
+	ClipboardMarkdown
+	// ClipboardHTML copies an HTML table. It is copied as rich text where
+	// the clipboard tools support it, so that office suites and Google
+	// Docs paste it as table with a header row.
+	ClipboardHTML
+)
+
+var clipboardFormatNames = []string{"tsv", "csv", "markdown", "html"}
+
+// ParseClipboardFormat returns the clipboard format with the given
+// name, i.e. "tsv", "csv", "markdown" or "html".
+func ParseClipboardFormat(name string) (ClipboardFormat, error) {
+	for i, formatName := range clipboardFormatNames {
+		if strings.EqualFold(name, formatName) {
+			return ClipboardFormat(i), nil
+		}
+	}
+	if strings.EqualFold(name, "md") {
+		return ClipboardMarkdown, nil
+	}
+
+	return ClipboardTSV, fmt.Errorf("unknown clipboard format %q, supported formats: %s",
+		name, strings.Join(clipboardFormatNames, ", "))
+}
+
+func (f ClipboardFormat) String() string {
+	if f < 0 || int(f) >= len(clipboardFormatNames) {
+		return fmt.Sprintf("ClipboardFormat(%d)", int(f))
+	}
+
+	return clipboardFormatNames[f]
+}
+
+// pasteHint tells where the copied Content can be pasted.
+func (f ClipboardFormat) pasteHint() string {
+	switch f {
+	case ClipboardMarkdown:
+		return "You can now paste it into an issue or a markdown document."
+	case ClipboardHTML:
+		return "You can now paste it into a document or an excel sheet."
+	case ClipboardCSV:
+		return "You can now paste it into a csv file."
+	}
+
+	return "You can now paste it into an excel sheet."
+}
+
+// copyToClipboard copies the Content to the clipboard in the format.
+// The values of TSV are sanitized according to the mode.
+func copyToClipboard(c Content, f ClipboardFormat, mode Sanitize) error {
+	var rd Renderer
+	switch f {
+	case ClipboardCSV:
+		rd = &CSVRenderer{}
+	case ClipboardMarkdown:
+		rd = &MarkdownRenderer{}
+	case ClipboardHTML:
+		rd = &HTMLRenderer{}
+	default:
+		return tsvPbcopy(c, mode)
+	}
+
+	var b bytes.Buffer
+	if err := rd.Render(c, &b); err != nil {
+		return err
+	}
+
+	if f == ClipboardHTML && !isSSH() {
+		if cmd := richTextCommand(b.String()); cmd != nil {
+			// xclip and wl-copy fork a process serving the clipboard
+			// once they read the HTML and exit, so Run returns then.
+			// Their output is left unset, as Wait would otherwise wait
+			// for the forked process holding it open.
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("%w: %v", ErrClipboard, err)
+			}
+			return nil
+		}
+	}
+
+	return writeClipboard(b.String())
+}
+
+// ttyPath is the terminal OSC 52 sequences are written to.
+const ttyPath = "/dev/tty"
+
+// writeClipboard copies the text to the clipboard. In SSH sessions, or
+// if no clipboard tool is available, it is sent to the terminal emulator
+// as OSC 52 escape sequence instead, which most terminal emulators copy
+// to the clipboard of the machine they run on.
+func writeClipboard(text string) error {
+	if !isSSH() {
+		err := clipboard.WriteAll(text)
+		if err == nil || writeOSC52(text) == nil {
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrClipboard, err)
+	}
+
+	if err := writeOSC52(text); err != nil {
+		return fmt.Errorf("%w: %v", ErrClipboard, err)
+	}
+
+	return nil
+}
+
+// writeOSC52 writes the OSC 52 sequence setting the clipboard to the
+// text to the terminal. Within tmux and screen, the sequence is wrapped
+// to pass it through to the outer terminal.
+func writeOSC52(text string) error {
+	tty, err := os.OpenFile(ttyPath, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = "\x1bP" + seq + "\x1b\\"
+	}
+
+	_, err = io.WriteString(tty, seq)
+	return err
+}
+
+// isSSH reports whether the process runs in an SSH session, where the
+// clipboard tools would copy to the clipboard of the remote machine.
+func isSSH() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+// richTextCommand returns a command copying the HTML to the clipboard
+// as rich text, or nil if no clipboard tool supporting it is available.
+// The HTML is then copied as plain text.
+func richTextCommand(html string) *exec.Cmd {
+	// without the charset, office suites assume Latin-1
+	html = `<meta charset="utf-8">` + html
+
+	var cmd *exec.Cmd
+	switch {
+	case runtime.GOOS == "darwin":
+		return exec.Command("osascript", "-e", fmt.Sprintf("set the clipboard to «data HTML%X»", html))
+	case os.Getenv("WAYLAND_DISPLAY") != "" && hasCommand("wl-copy"):
+		cmd = exec.Command("wl-copy", "--type", "text/html")
+	case hasCommand("xclip"):
+		cmd = exec.Command("xclip", "-selection", "clipboard", "-t", "text/html")
+	default:
+		return nil
+	}
+	// Stdout and Stderr stay unset, see copyToClipboard
+	cmd.Stdin = strings.NewReader(html)
+
+	return cmd
+}
+
+func hasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// ErrClipboardEmpty is returned by ReadClipboard if the clipboard holds
+// no text.
+var ErrClipboardEmpty = errors.New("the clipboard is empty")
+
+// ReadClipboard returns a reader of the text in the clipboard, e.g.
+// cells copied from a spreadsheet, which are copied as TSV. The format
+// can be detected with DetectParser.
+func ReadClipboard() (io.Reader, error) {
+	text, err := clipboard.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clipboard: %w", err)
+	}
+
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrClipboardEmpty
+	}
+
+	return strings.NewReader(text), nil
+}
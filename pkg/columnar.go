@@ -0,0 +1,279 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// maxInternLen is the length up to which Columnar shares the memory
+	// of equal values. Longer values are rarely repeated.
+	maxInternLen = 64
+	// internSample is the number of values after which Columnar stops
+	// sharing the values of a column if most of them are distinct, as
+	// the lookup table would take more memory than it saves.
+	internSample = 1024
+)
+
+// Columnar is a column-oriented alternative to Content. The values are
+// held in one slice per column and copied out of the parsed records,
+// and equal values of a column share their memory. As every value still
+// takes a string header, the savings are modest: a table of 20 columns
+// repeating short values takes about a quarter less memory than a
+// Content, while one of mostly distinct values takes about a fifth more,
+// see BenchmarkColumnar. Column-wise operations like SortBy and
+// Aggregate are faster. It is converted with Content for rendering,
+// which copies the rows, so it saves memory only while it is the sole
+// copy of the table.
+type Columnar struct {
+	header  []string
+	columns [][]string
+	// interned are the shared values of every column, nil for columns
+	// which are no longer interned.
+	interned []map[string]string
+}
+
+// NewColumnar returns an empty Columnar with the given columns.
+func NewColumnar(header []string) *Columnar {
+	t := &Columnar{
+		header:   header,
+		columns:  make([][]string, len(header)),
+		interned: make([]map[string]string, len(header)),
+	}
+	for j := range t.interned {
+		t.interned[j] = map[string]string{}
+	}
+
+	return t
+}
+
+// ToColumnar converts the Content to a Columnar.
+func ToColumnar(c Content) *Columnar {
+	t := NewColumnar(c.header)
+	for _, row := range c.rows {
+		t.Append(row)
+	}
+
+	return t
+}
+
+// ParseColumnar parses the document into a Columnar row by row, so that
+// the rows of the document are never held in memory.
+func ParseColumnar(p StreamParser, r io.Reader) (*Columnar, error) {
+	var t *Columnar
+
+	err := p.ParseStream(r, func(header []string) error {
+		t = NewColumnar(header)
+		return nil
+	}, func(row []string) error {
+		t.Append(row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return NewColumnar(nil), nil
+	}
+	t.compact()
+
+	return t, nil
+}
+
+// compact releases the spare capacity of the columns and the lookup
+// tables of their shared values, which are no longer needed once the
+// document is read. Rows appended later are not interned.
+func (t *Columnar) compact() {
+	for j, values := range t.columns {
+		t.columns[j] = append([]string(nil), values...)
+		t.interned[j] = nil
+	}
+}
+
+// Append adds a row. Missing values are empty, surplus values are
+// dropped.
+func (t *Columnar) Append(row []string) {
+	for j := range t.columns {
+		t.columns[j] = append(t.columns[j], t.intern(j, cell(row, j)))
+
+		if len(t.columns[j]) == internSample && len(t.interned[j]) > internSample/2 {
+			t.interned[j] = nil
+		}
+	}
+}
+
+// intern returns the shared copy of the value of column j. Values are
+// copied, as parsers like encoding/csv return substrings of the whole
+// record, which would keep the record in memory.
+func (t *Columnar) intern(j int, value string) string {
+	shared := t.interned[j]
+	if shared == nil || len(value) > maxInternLen {
+		return strings.Clone(value)
+	}
+
+	if s, ok := shared[value]; ok {
+		return s
+	}
+	value = strings.Clone(value)
+	shared[value] = value
+
+	return value
+}
+
+// Header returns the column names. The returned slice must not be
+// modified.
+func (t *Columnar) Header() []string {
+	return t.header
+}
+
+// Len returns the number of rows.
+func (t *Columnar) Len() int {
+	if len(t.columns) == 0 {
+		return 0
+	}
+
+	return len(t.columns[0])
+}
+
+// At returns the value in row i and column j. At panics if i or j is
+// out of range.
+func (t *Columnar) At(i, j int) string {
+	return t.columns[j][i]
+}
+
+// Column returns the values of the named column, or false if there is
+// no such column. The returned slice must not be modified.
+func (t *Columnar) Column(name string) ([]string, bool) {
+	j := columnIndex(t.header, name)
+	if j < 0 {
+		return nil, false
+	}
+
+	return t.columns[j], true
+}
+
+// SortBy sorts the rows in place like the SortBy Transform, e.g. by
+// "age desc, name asc". Numbers and dates are parsed once per value
+// instead of once per comparison.
+func (t *Columnar) SortBy(spec string) error {
+	column := func(j int) []string { return t.columns[j] }
+	keys, err := parseSortSpec(spec, t.header, column)
+	if err != nil {
+		return err
+	}
+
+	compares := make([]func(a, b int) int, len(keys))
+	for k, key := range keys {
+		compares[k] = columnComparer(t.columns[key.index], key.kind)
+	}
+
+	order := make([]int, t.Len())
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		for k, key := range keys {
+			cmp := compares[k](order[a], order[b])
+			if cmp == 0 {
+				continue
+			}
+			if key.desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	for j, values := range t.columns {
+		sorted := make([]string, len(values))
+		for i, idx := range order {
+			sorted[i] = values[idx]
+		}
+		t.columns[j] = sorted
+	}
+
+	return nil
+}
+
+// columnComparer returns a function comparing the values of the column
+// at two row indices like compareValues, with numbers and dates parsed
+// in advance.
+func columnComparer(values []string, kind sortKind) func(a, b int) int {
+	empty := func(a, b int) (int, bool) {
+		switch {
+		case values[a] == values[b]:
+			return 0, true
+		case values[a] == "":
+			return -1, true
+		case values[b] == "":
+			return 1, true
+		}
+		return 0, false
+	}
+
+	switch kind {
+	case sortNumber:
+		numbers := make([]float64, len(values))
+		for i, v := range values {
+			numbers[i], _ = parseNumber(v)
+		}
+		return func(a, b int) int {
+			if cmp, ok := empty(a, b); ok {
+				return cmp
+			}
+			return compareFloats(numbers[a], numbers[b])
+		}
+	case sortTime:
+		times := make([]time.Time, len(values))
+		for i, v := range values {
+			times[i], _ = parseTime(v)
+		}
+		return func(a, b int) int {
+			if cmp, ok := empty(a, b); ok {
+				return cmp
+			}
+			return compareTimes(times[a], times[b])
+		}
+	}
+
+	return func(a, b int) int {
+		return compareValues(values[a], values[b], sortString)
+	}
+}
+
+// Aggregate aggregates the named column, e.g. Aggregate("price", Sum).
+func (t *Columnar) Aggregate(name string, fn AggFunc) (string, error) {
+	values, ok := t.Column(name)
+	if !ok {
+		return "", fmt.Errorf("column %q does not exist", name)
+	}
+
+	v, err := fn(values)
+	if err != nil {
+		return "", fmt.Errorf("column %q: %w", name, err)
+	}
+
+	return v, nil
+}
+
+// Content converts the Columnar to a Content, e.g. to render it.
+func (t *Columnar) Content() Content {
+	rows := make([][]string, t.Len())
+	for i := range rows {
+		row := make([]string, len(t.columns))
+		for j, values := range t.columns {
+			row[j] = values[i]
+		}
+		rows[i] = row
+	}
+
+	return Content{
+		header: t.header,
+		rows:   rows,
+	}
+}
@@ -0,0 +1,135 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// benchmarkCSV returns a csv document of 20 columns. The values repeat
+// within a few hundred distinct values if repeated is set, otherwise
+// they are mostly distinct.
+func benchmarkCSV(rows int, repeated bool) []byte {
+	var b bytes.Buffer
+	for j := 0; j < 20; j++ {
+		if j > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "col%d", j)
+	}
+	b.WriteByte('\n')
+
+	for i := 0; i < rows; i++ {
+		for j := 0; j < 20; j++ {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			if repeated {
+				fmt.Fprintf(&b, "%d.%d", (i*7+j)%100, j%4)
+			} else {
+				fmt.Fprintf(&b, "%d.%04d", i, j*37%10000)
+			}
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes()
+}
+
+// retained returns the bytes of the heap still held after parse, whose
+// result is kept alive until it is measured.
+func retained(parse func() interface{}) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	v := parse()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(v)
+
+	if after.HeapAlloc < before.HeapAlloc {
+		return 0
+	}
+	return after.HeapAlloc - before.HeapAlloc
+}
+
+// BenchmarkColumnar compares the memory retained by a Content and a
+// Columnar of the same document, reported as retained-B/op.
+func BenchmarkColumnar(b *testing.B) {
+	for _, repeated := range []bool{true, false} {
+		data := benchmarkCSV(20000, repeated)
+		name := "distinct"
+		if repeated {
+			name = "repeated"
+		}
+
+		b.Run(name+"/Content", func(b *testing.B) {
+			b.ReportAllocs()
+			var bytes uint64
+			for i := 0; i < b.N; i++ {
+				bytes += retained(func() interface{} {
+					c, err := (&CSVParser{}).Parse(strings.NewReader(string(data)))
+					if err != nil {
+						b.Fatal(err)
+					}
+					return c
+				})
+			}
+			b.ReportMetric(float64(bytes)/float64(b.N), "retained-B/op")
+		})
+
+		b.Run(name+"/Columnar", func(b *testing.B) {
+			b.ReportAllocs()
+			var bytes uint64
+			for i := 0; i < b.N; i++ {
+				bytes += retained(func() interface{} {
+					t, err := ParseColumnar(&CSVParser{}, strings.NewReader(string(data)))
+					if err != nil {
+						b.Fatal(err)
+					}
+					return t
+				})
+			}
+			b.ReportMetric(float64(bytes)/float64(b.N), "retained-B/op")
+		})
+	}
+}
+
+func TestColumnar(t *testing.T) {
+	data := string(benchmarkCSV(internSample*2, false))
+	c, err := (&CSVParser{}).Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbl, err := ParseColumnar(&CSVParser{}, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := tbl.Content()
+	if fmt.Sprint(got.header, got.rows) != fmt.Sprint(c.header, c.rows) {
+		t.Fatal("the Columnar differs from the Content")
+	}
+}
+
+func TestColumnarIntern(t *testing.T) {
+	tbl := NewColumnar([]string{"repeated", "distinct"})
+	for i := 0; i < internSample; i++ {
+		record := fmt.Sprintf("%d,%d", i%3, i)
+		tbl.Append(strings.Split(record, ","))
+	}
+
+	if tbl.interned[0] == nil || len(tbl.interned[0]) != 3 {
+		t.Errorf("the repeated column has %d shared values, want 3", len(tbl.interned[0]))
+	}
+	if tbl.interned[1] != nil {
+		t.Error("the column of distinct values is still interned")
+	}
+	if a, b := tbl.At(0, 0), tbl.At(3, 0); unsafe.StringData(a) != unsafe.StringData(b) {
+		t.Error("equal values do not share their memory")
+	}
+}
@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic  = []byte("\x1f\x8b")
+	zstdMagic  = []byte("\x28\xb5\x2f\xfd")
+	bzip2Magic = []byte("BZh")
+
+	// bzip2 streams start with the magic of a block or, if empty, of
+	// the end of the stream after "BZh" and the block size
+	bzip2Blocks = [][]byte{[]byte("\x31\x41\x59\x26\x53\x59"), []byte("\x17\x72\x45\x38\x50\x90")}
+)
+
+// compressionExts maps the extensions of compressed files to their
+// compression.
+var compressionExts = map[string]string{
+	".gz":   "gzip",
+	".gzip": "gzip",
+	".zst":  "zstd",
+	".zstd": "zstd",
+	".bz2":  "bzip2",
+}
+
+// Decompress returns a reader of the decompressed content of r if it is
+// compressed with gzip, zstd or bzip2, which is detected by the
+// extension of the file name, e.g. ".gz", or by the first bytes of the
+// content. Otherwise, the content is read as it is. Closing the reader
+// releases the decompressor, but does not close r.
+func Decompress(r io.Reader, name string) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+
+	compression := compressionExts[strings.ToLower(filepath.Ext(name))]
+	if compression == "" {
+		head, err := br.Peek(10)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		compression = detectCompression(head)
+	}
+
+	switch compression {
+	case "gzip":
+		return gzip.NewReader(br)
+	case "zstd":
+		d, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	case "bzip2":
+		return io.NopCloser(bzip2.NewReader(br)), nil
+	}
+
+	return io.NopCloser(br), nil
+}
+
+// detectCompression returns the compression of the content starting
+// with head, or "" if it is not compressed.
+func detectCompression(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		return "gzip"
+	case bytes.HasPrefix(head, zstdMagic):
+		return "zstd"
+	case bytes.HasPrefix(head, bzip2Magic) && len(head) == 10 && head[3] >= '1' && head[3] <= '9':
+		for _, block := range bzip2Blocks {
+			if bytes.Equal(head[4:], block) {
+				return "bzip2"
+			}
+		}
+	}
+
+	return ""
+}
+
+// TrimCompressionExt removes the extension of a compressed file from
+// the name, e.g. "users.json" is returned for "users.json.gz", so that
+// the format can be looked up by the remaining extension.
+func TrimCompressionExt(name string) string {
+	ext := filepath.Ext(name)
+	if _, ok := compressionExts[strings.ToLower(ext)]; ok {
+		return strings.TrimSuffix(name, ext)
+	}
+
+	return name
+}
@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SourceColumn is the name of the column added by Concat, which holds
+// the name of the source of every row.
+const SourceColumn = "__source"
+
+// Source is a Content along with the name of its origin, e.g. the file
+// it was parsed from.
+type Source struct {
+	Name    string
+	Content Content
+}
+
+// Concat appends the rows of the sources. All sources must have the
+// same columns, which may be in a different order than in the first
+// source. If addSource is set, a leading SourceColumn holds the name of
+// the source of every row.
+func Concat(sources []Source, addSource bool) (Content, error) {
+	if len(sources) == 0 {
+		return Content{}, nil
+	}
+
+	first := sources[0]
+	header := first.Content.header
+
+	var rows [][]string
+	for _, s := range sources {
+		indices, ok := columnMapping(header, s.Content.header)
+		if !ok {
+			return Content{}, fmt.Errorf("columns of %s (%s) differ from the ones of %s (%s)",
+				s.Name, strings.Join(s.Content.header, ", "), first.Name, strings.Join(header, ", "))
+		}
+
+		for i := range s.Content.rows {
+			row := make([]string, 0, len(indices)+1)
+			if addSource {
+				row = append(row, s.Name)
+			}
+			for _, idx := range indices {
+				row = append(row, s.Content.At(i, idx))
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	if addSource {
+		header = append([]string{SourceColumn}, header...)
+	}
+
+	return Content{
+		header: header,
+		rows:   rows,
+	}, nil
+}
+
+// columnMapping returns the index in other of every column of header.
+// It reports false if the columns differ.
+func columnMapping(header, other []string) ([]int, bool) {
+	if len(header) != len(other) {
+		return nil, false
+	}
+
+	positions := map[string][]int{}
+	for i, name := range other {
+		positions[name] = append(positions[name], i)
+	}
+
+	indices := make([]int, len(header))
+	for i, name := range header {
+		p := positions[name]
+		if len(p) == 0 {
+			return nil, false
+		}
+		indices[i], positions[name] = p[0], p[1:]
+	}
+
+	return indices, true
+}
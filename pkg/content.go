@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// cancelRows is the number of rows after which long loops over the rows
+// check whether their context is done, see Content.canceled.
+const cancelRows = 256
+
+// Content is the intermediate representation before it is converted
+// to a table format.
+type Content struct {
+	header []string
+	rows   [][]string
+	// types are the column types if they were inferred by InferTypes.
+	types []ColumnType
+	// ctx is the context of FormatContext, which is set before every
+	// transformation and rendering, so that they stop between rows.
+	ctx context.Context
+}
+
+// NewContent creates a Content from a header and its rows. The slices
+// are not copied, so they must not be modified afterwards.
+func NewContent(header []string, rows [][]string) Content {
+	return Content{
+		header: header,
+		rows:   rows,
+	}
+}
+
+// Header returns the column names. The returned slice must not be
+// modified.
+func (c Content) Header() []string {
+	return c.header
+}
+
+// Rows returns the rows without the header. The returned slices must
+// not be modified.
+func (c Content) Rows() [][]string {
+	return c.rows
+}
+
+// At returns the value in row i and column j. An empty string is
+// returned if the row has fewer than j+1 values. At panics if i is out
+// of range.
+func (c Content) At(i, j int) string {
+	row := c.rows[i]
+	if j < 0 || j >= len(row) {
+		return ""
+	}
+
+	return row[j]
+}
+
+// canceled returns the error of the context of the Content once it is
+// done, checking it every cancelRows rows. Loops over the rows call it
+// with the index of the row.
+func (c Content) canceled(i int) error {
+	if c.ctx == nil {
+		return nil
+	}
+
+	return canceled(c.ctx, i)
+}
+
+// canceled returns the error of the context once it is done, checking
+// it every cancelRows rows.
+func canceled(ctx context.Context, i int) error {
+	if i%cancelRows != 0 {
+		return nil
+	}
+
+	return ctx.Err()
+}
+
+// columnIndex returns the index of the named column or -1 if there is
+// no such column. Exact matches take precedence over case-insensitive
+// ones.
+func (c Content) columnIndex(name string) int {
+	return columnIndex(c.header, name)
+}
+
+// columnIndex returns the index of the named column of the header, see
+// Content.columnIndex.
+func columnIndex(header []string, name string) int {
+	for i, h := range header {
+		if h == name {
+			return i
+		}
+	}
+
+	for i, h := range header {
+		if strings.EqualFold(h, name) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// uniqueNames returns the names with repeated ones renamed by appending
+// _2, _3, ..., skipping names which are taken, e.g. "id", "id_2". It
+// also returns the indices of the renamed names.
+func uniqueNames(names []string) ([]string, []int) {
+	taken := make(map[string]bool, len(names))
+	for _, name := range names {
+		taken[name] = true
+	}
+
+	out := append([]string(nil), names...)
+	seen := map[string]bool{}
+	var renamed []int
+	for i, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			continue
+		}
+
+		for n := 2; ; n++ {
+			candidate := name + "_" + strconv.Itoa(n)
+			if !taken[candidate] {
+				out[i] = candidate
+				taken[candidate] = true
+				break
+			}
+		}
+		renamed = append(renamed, i)
+	}
+
+	return out, renamed
+}
+
+// column returns all values of the column at index i.
+func (c Content) column(i int) []string {
+	values := make([]string, len(c.rows))
+	for j := range c.rows {
+		values[j] = c.At(j, i)
+	}
+
+	return values
+}
@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"context"
+	"io"
+)
+
+// FormatContext is Format, which stops once the context is canceled or
+// its deadline is exceeded, and then returns the error of the context.
+// Stream parsers, the transformations and the renderers of this package
+// check the context between rows. Other parsers read from and all
+// renderers write to wrappers, which fail once the context is done, so
+// that parsers and renderers unaware of contexts stop as well.
+func FormatContext(ctx context.Context, p Parser, r io.Reader, w io.Writer, opts ...Option) error {
+	o := newOptions(opts)
+	if o.nulls != nil {
+		p = withNullStrings(p, o.nulls)
+	}
+
+	if o.charset != "" {
+		var err error
+		if r, err = DecodeReader(r, o.charset); err != nil {
+			return err
+		}
+	}
+
+	if sp, ok := p.(StreamParser); ok && o.batchSize > 0 {
+		err := formatStream(ctx, sp, contextReaderOf(ctx, r), contextWriterOf(ctx, w), o.batchSize, o)
+		return contextError(ctx, err)
+	}
+
+	c, err := parse(ctx, p, contextReaderOf(ctx, r), o)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+
+	err = formatContent(ctx, c, contextWriterOf(ctx, w), o)
+	return contextError(ctx, err)
+}
+
+// FormatContentContext is FormatContent, which stops like FormatContext
+// once the context is done.
+func FormatContentContext(ctx context.Context, c Content, w io.Writer, opts ...Option) error {
+	err := formatContent(ctx, c, contextWriterOf(ctx, w), newOptions(opts))
+	return contextError(ctx, err)
+}
+
+// contextError returns the error of the context if it is done, as err
+// likely is a consequence of it, possibly wrapped without %w.
+func contextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+// contextReaderOf returns a contextReader of the reader, or the reader
+// itself if the context is never done, e.g. for Format, so that parsers
+// may still detect e.g. files.
+func contextReaderOf(ctx context.Context, r io.Reader) io.Reader {
+	if ctx.Done() == nil {
+		return r
+	}
+
+	return &contextReader{ctx: ctx, r: r}
+}
+
+// contextWriterOf returns a contextWriter of the writer, or the writer
+// itself if the context is never done, so that renderers may still
+// detect terminals.
+func contextWriterOf(ctx context.Context, w io.Writer) io.Writer {
+	if ctx.Done() == nil {
+		return w
+	}
+
+	return &contextWriter{ctx: ctx, w: w}
+}
+
+// contextReader is a reader failing once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p)
+}
+
+// contextWriter is a writer failing once its context is done.
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w *contextWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return w.w.Write(p)
+}
@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// longCSV returns a csv document of n rows.
+func longCSV(n int) string {
+	var b strings.Builder
+	b.WriteString("id,name\n")
+	for i := 0; i < n; i++ {
+		b.WriteString("1,apple\n")
+	}
+
+	return b.String()
+}
+
+func TestFormatContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	err := FormatContext(ctx, &CSVParser{}, strings.NewReader(longCSV(1000)), &out)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+	if out.Len() > 0 {
+		t.Errorf("wrote %q", out.String())
+	}
+}
+
+// TestCanceledBetweenRows checks the loops over rows, which stop even if
+// the input is read and the writer is never used.
+func TestCanceledBetweenRows(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("parse", func(t *testing.T) {
+		_, err := parse(ctx, &CSVParser{}, strings.NewReader(longCSV(1000)), newOptions(nil))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want %v", err, context.Canceled)
+		}
+	})
+
+	c := parseCSV(t, longCSV(1000))
+	c.ctx = ctx
+	transforms := map[string]Transform{
+		"filter":  Filter("id > 0"),
+		"replace": Replace(nil, "a", "b"),
+		"compute": ComputeColumn("x", "id * 2"),
+		"mask":    Mask(map[string]MaskMode{"name": MaskFull}),
+		"group":   GroupBy([]string{"name"}, nil),
+	}
+	for name, transform := range transforms {
+		t.Run(name, func(t *testing.T) {
+			if _, err := transform(c); !errors.Is(err, context.Canceled) {
+				t.Errorf("got %v, want %v", err, context.Canceled)
+			}
+		})
+	}
+
+	t.Run("render", func(t *testing.T) {
+		if err := (&TableRenderer{}).Render(c, &bytes.Buffer{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestFormatIsFormatContext(t *testing.T) {
+	var a, b bytes.Buffer
+	opts := []Option{WithFilter("id > 0"), WithRenderer(&CSVRenderer{})}
+	if err := Format(&CSVParser{}, strings.NewReader(longCSV(3)), &a, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if err := FormatContext(context.Background(), &CSVParser{}, strings.NewReader(longCSV(3)), &b, opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.String() != b.String() {
+		t.Errorf("Format wrote\n%s\nFormatContext wrote\n%s", a.String(), b.String())
+	}
+}
@@ -0,0 +1,17 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+)
+
+// CountRenderer is a renderer implementation that writes nothing but
+// the number of rows, e.g. for use in shell scripts.
+type CountRenderer struct{}
+
+// Render writes the number of rows of the Content to the writer.
+func (cr *CountRenderer) Render(c Content, w io.Writer) error {
+	_, err := fmt.Fprintln(w, len(c.rows))
+
+	return err
+}
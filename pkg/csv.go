@@ -0,0 +1,325 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVParser is a parser implementation that parses CSV documents.
+type CSVParser struct {
+	// Delimiter is the field delimiter, a comma is used if it is unset.
+	Delimiter rune
+	// Quote is the character used to quote fields, a double quote is
+	// used if it is unset. It must be an ASCII character.
+	Quote rune
+	// NoHeader treats the first record as data and names the columns
+	// col1, col2, ... instead.
+	NoHeader bool
+	// AutoHeader decides with DetectHeader whether the first record is
+	// a header, comparing it with the records following it. NoHeader is
+	// ignored if it is set.
+	AutoHeader bool
+	// Lenient accepts records whose number of fields differs from the
+	// header as well as bare quotes in unquoted fields. Short records
+	// are padded with empty values, long records are truncated unless
+	// MergeOverflow is set. Every adjustment is recorded in Warnings.
+	Lenient bool
+	// MergeOverflow joins the surplus fields of long records into the
+	// last column instead of dropping them. It requires Lenient.
+	MergeOverflow bool
+	// StrictHeader rejects headers naming a column more than once.
+	// Otherwise the repeated names are renamed, e.g. the second "id" to
+	// "id_2", and every renaming is recorded in Warnings.
+	StrictHeader bool
+	// Warnings lists the renamed columns and the records adjusted in
+	// lenient mode by the last call to Parse or ParseStream.
+	Warnings []Warning
+	// Workers is the number of goroutines Parse splits large inputs
+	// between, runtime.NumCPU() is used if it is unset. Inputs are read
+	// into memory first to split them. Lenient parsing and ParseStream
+	// are always sequential.
+	Workers int
+}
+
+// Warning describes a problem in the input which did not abort parsing.
+type Warning struct {
+	// Line is the line number the record starts at.
+	Line    int
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d: %s", w.Line, w.Message)
+}
+
+func (c *CSVParser) newReader(reader io.Reader) (*csv.Reader, error) {
+	if c.swapQuote() {
+		if c.Quote >= 0x80 {
+			return nil, fmt.Errorf("quote %q is not an ASCII character", c.Quote)
+		}
+		reader = &quoteSwapReader{r: reader, quote: byte(c.Quote)}
+	}
+
+	r := csv.NewReader(reader)
+	if c.Delimiter != 0 {
+		r.Comma = c.Delimiter
+	}
+	if c.Lenient {
+		r.FieldsPerRecord = -1
+		r.LazyQuotes = true
+	}
+
+	return r, nil
+}
+
+func (c *CSVParser) swapQuote() bool {
+	return c.Quote != 0 && c.Quote != '"'
+}
+
+// read returns the next record, undoing the quote swap if necessary.
+func (c *CSVParser) read(r *csv.Reader) ([]string, error) {
+	record, err := r.Read()
+	if err != nil || !c.swapQuote() {
+		return record, err
+	}
+
+	for i, v := range record {
+		record[i] = strings.Map(func(r rune) rune {
+			switch r {
+			case '"':
+				return c.Quote
+			case c.Quote:
+				return '"'
+			}
+			return r
+		}, v)
+	}
+
+	return record, nil
+}
+
+// Parse converts the content of a reader to the Content representation.
+// Inputs of several megabytes are parsed in parallel, see Workers.
+func (c *CSVParser) Parse(reader io.Reader) (Content, error) {
+	if c.Lenient || c.workers() < 2 {
+		return c.parseSequential(reader)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return Content{}, err
+	}
+	if len(data) < parallelMinSize {
+		return c.parseSequential(bytes.NewReader(data))
+	}
+
+	c.Warnings = nil
+	return c.parseParallel(data, c.chunkSize(len(data)))
+}
+
+func (c *CSVParser) parseSequential(reader io.Reader) (Content, error) {
+	var out Content
+
+	err := c.ParseStream(reader, func(header []string) error {
+		out.header = header
+		return nil
+	}, func(row []string) error {
+		out.rows = append(out.rows, row)
+		return nil
+	})
+	if err != nil {
+		return Content{}, err
+	}
+
+	return out, nil
+}
+
+// headerSample is the number of records following the first one which
+// AutoHeader compares it with.
+const headerSample = 20
+
+// csvRecord is a record read ahead and the line it starts at.
+type csvRecord struct {
+	row  []string
+	line int
+}
+
+// ParseStream reads the CSV document record by record.
+func (c *CSVParser) ParseStream(reader io.Reader, onHeader, onRow RowFunc) error {
+	c.Warnings = nil
+
+	tracker := newInputTracker(reader)
+	r, err := c.newReader(tracker)
+	if err != nil {
+		return err
+	}
+
+	first, err := c.read(r)
+	if err != nil {
+		return csvError(tracker, err)
+	}
+	positions := make([][2]int, len(first))
+	for i := range first {
+		positions[i][0], positions[i][1] = r.FieldPos(i)
+	}
+
+	noHeader := c.NoHeader
+	var ahead []csvRecord
+	if c.AutoHeader {
+		records := [][]string{first}
+		for len(ahead) < headerSample {
+			row, err := c.read(r)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return csvError(tracker, err)
+			}
+
+			line, _ := r.FieldPos(0)
+			ahead = append(ahead, csvRecord{row: row, line: line})
+			records = append(records, row)
+		}
+		noHeader = !DetectHeader(records)
+	}
+
+	if noHeader {
+		if err := onHeader(syntheticHeader(len(first))); err != nil {
+			return err
+		}
+		if err := onRow(first); err != nil {
+			return err
+		}
+	} else {
+		unique, err := c.uniqueHeader(tracker, first, positions)
+		if err != nil {
+			return err
+		}
+		if err := onHeader(unique); err != nil {
+			return err
+		}
+	}
+
+	emit := func(row []string, line int) error {
+		if c.Lenient {
+			row = c.fit(line, row, len(first))
+		}
+		return onRow(row)
+	}
+
+	for _, record := range ahead {
+		if err := emit(record.row, record.line); err != nil {
+			return err
+		}
+	}
+
+	for {
+		row, err := c.read(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return csvError(tracker, err)
+		}
+
+		line, _ := r.FieldPos(0)
+		if err := emit(row, line); err != nil {
+			return err
+		}
+	}
+}
+
+// uniqueHeader renames the repeated names of the header, see
+// StrictHeader. The positions hold the line and column of every field.
+func (c *CSVParser) uniqueHeader(t *inputTracker, header []string, positions [][2]int) ([]string, error) {
+	unique, renamed := uniqueNames(header)
+	for _, i := range renamed {
+		line, column := positions[i][0], positions[i][1]
+		if c.StrictHeader {
+			return nil, t.lineColumnError(line, column, fmt.Errorf("duplicate column %q", header[i]))
+		}
+
+		c.Warnings = append(c.Warnings, Warning{
+			Line:    line,
+			Message: fmt.Sprintf("duplicate column %q renamed to %q", header[i], unique[i]),
+		})
+	}
+
+	return unique, nil
+}
+
+// fit pads or shortens the record starting at the line to the given
+// number of fields.
+func (c *CSVParser) fit(line int, record []string, fields int) []string {
+	if len(record) == fields {
+		return record
+	}
+
+	warn := func(format string, args ...interface{}) {
+		c.Warnings = append(c.Warnings, Warning{Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(record) < fields {
+		warn("%d fields instead of %d, padded with empty values", len(record), fields)
+		return append(record, make([]string, fields-len(record))...)
+	}
+
+	if c.MergeOverflow && fields > 0 {
+		warn("%d fields instead of %d, merged surplus fields into the last column", len(record), fields)
+		delimiter := ","
+		if c.Delimiter != 0 {
+			delimiter = string(c.Delimiter)
+		}
+		record[fields-1] = strings.Join(record[fields-1:], delimiter)
+		return record[:fields]
+	}
+
+	warn("%d fields instead of %d, dropped surplus fields", len(record), fields)
+	return record[:fields]
+}
+
+// csvError converts errors of encoding/csv into a ParseError.
+func csvError(t *inputTracker, err error) error {
+	var perr *csv.ParseError
+	if errors.As(err, &perr) {
+		return t.lineColumnError(perr.Line, perr.Column, perr.Err)
+	}
+
+	return err
+}
+
+// syntheticHeader returns the column names col1 to coln.
+func syntheticHeader(n int) []string {
+	header := make([]string, n)
+	for i := range header {
+		header[i] = "col" + strconv.Itoa(i+1)
+	}
+
+	return header
+}
+
+// quoteSwapReader exchanges the quote character with a double quote,
+// the only quote character supported by encoding/csv.
+type quoteSwapReader struct {
+	r     io.Reader
+	quote byte
+}
+
+func (q *quoteSwapReader) Read(p []byte) (int, error) {
+	n, err := q.r.Read(p)
+	for i := 0; i < n; i++ {
+		switch p[i] {
+		case q.quote:
+			p[i] = '"'
+		case '"':
+			p[i] = q.quote
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// parseCSV parses the csv document, failing the test on errors.
+func parseCSV(t *testing.T, s string) Content {
+	t.Helper()
+
+	c, err := (&CSVParser{}).Parse(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+// toCSV renders the Content as csv, to compare it with the expected
+// document.
+func toCSV(t *testing.T, c Content) string {
+	t.Helper()
+
+	var b bytes.Buffer
+	if err := (&CSVRenderer{}).Render(c, &b); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.String()
+}
+
+func TestCSVParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser CSVParser
+		in     string
+		want   string
+	}{
+		{
+			name: "plain",
+			in:   "name,price\napple,1.5\npear,2\n",
+			want: "name,price\napple,1.5\npear,2\n",
+		},
+		{
+			name: "quoted",
+			in:   "name,note\napple,\"sweet, red\"\npear,\"line\nbreak\"\n",
+			want: "name,note\napple,\"sweet, red\"\npear,\"line\nbreak\"\n",
+		},
+		{
+			name:   "delimiter",
+			parser: CSVParser{Delimiter: ';'},
+			in:     "name;price\napple;1,5\n",
+			want:   "name,price\napple,\"1,5\"\n",
+		},
+		{
+			name:   "no header",
+			parser: CSVParser{NoHeader: true},
+			in:     "apple,1.5\npear,2\n",
+			want:   "col1,col2\napple,1.5\npear,2\n",
+		},
+		{
+			name: "repeated names",
+			in:   "id,id\n1,2\n",
+			want: "id,id_2\n1,2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.parser
+			c, err := p.Parse(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := toCSV(t, c); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVParserErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"unterminated quote", "name,note\napple,\"sweet\n"},
+		{"long record", "name,price\napple,1.5,extra\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (&CSVParser{}).Parse(strings.NewReader(tt.in)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestCSVParseStream(t *testing.T) {
+	var header []string
+	var rows [][]string
+	err := (&CSVParser{}).ParseStream(strings.NewReader("name,price\napple,1.5\npear,2\n"), func(h []string) error {
+		header = h
+		return nil
+	}, func(row []string) error {
+		rows = append(rows, append([]string(nil), row...))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := toCSV(t, NewContent(header, rows)); got != "name,price\napple,1.5\npear,2\n" {
+		t.Errorf("got\n%s", got)
+	}
+}
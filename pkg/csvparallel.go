@@ -0,0 +1,173 @@
+package pkg
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"sync"
+)
+
+const (
+	// parallelMinSize is the input size below which CSV is parsed
+	// sequentially, as starting workers does not pay off.
+	parallelMinSize = 4 << 20
+	// minChunkSize is the minimum size of the chunks parsed in parallel.
+	minChunkSize = 1 << 20
+)
+
+// workers returns the number of goroutines Parse uses.
+func (c *CSVParser) workers() int {
+	if c.Workers > 0 {
+		return c.Workers
+	}
+
+	return runtime.NumCPU()
+}
+
+// parseParallel splits the data into chunks of whole records of about
+// size bytes, which are parsed by concurrent workers and merged in
+// order. The chunks are split at line breaks outside of quoted fields.
+// If any chunk fails, the data is parsed sequentially to report the
+// error like ParseStream.
+func (c *CSVParser) parseParallel(data []byte, size int) (Content, error) {
+	chunks := c.splitChunks(data, size)
+	if len(chunks) < 2 {
+		return c.parseSequential(bytes.NewReader(data))
+	}
+
+	results := make([][][]string, len(chunks))
+	failed := make([]bool, len(chunks))
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < c.workers() && n < len(chunks); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				results[i], failed[i] = c.parseChunk(chunks[i])
+			}
+		}()
+	}
+	for i := range chunks {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	for _, f := range failed {
+		if f {
+			return c.parseSequential(bytes.NewReader(data))
+		}
+	}
+
+	records := 0
+	for _, r := range results {
+		records += len(r)
+	}
+	if records == 0 {
+		return c.parseSequential(bytes.NewReader(data))
+	}
+
+	var out Content
+	out.rows = make([][]string, 0, records)
+	for _, r := range results {
+		out.rows = append(out.rows, r...)
+	}
+
+	// encoding/csv requires all records to have the same number of
+	// fields, which the workers cannot check across chunks
+	fields := len(out.rows[0])
+	for _, row := range out.rows {
+		if len(row) != fields {
+			return c.parseSequential(bytes.NewReader(data))
+		}
+	}
+
+	noHeader := c.NoHeader
+	if c.AutoHeader {
+		sample := out.rows
+		if len(sample) > headerSample+1 {
+			sample = sample[:headerSample+1]
+		}
+		noHeader = !DetectHeader(sample)
+	}
+
+	if noHeader {
+		out.header = syntheticHeader(fields)
+	} else {
+		if _, renamed := uniqueNames(out.rows[0]); len(renamed) > 0 {
+			// renamed columns are reported with their position
+			return c.parseSequential(bytes.NewReader(data))
+		}
+		out.header, out.rows = out.rows[0], out.rows[1:]
+	}
+
+	return out, nil
+}
+
+// parseChunk parses the records of a chunk. It reports true if the
+// chunk is not valid CSV.
+func (c *CSVParser) parseChunk(chunk []byte) ([][]string, bool) {
+	r, err := c.newReader(bytes.NewReader(chunk))
+	if err != nil {
+		return nil, true
+	}
+	r.FieldsPerRecord = -1
+
+	var records [][]string
+	for {
+		record, err := c.read(r)
+		if err == io.EOF {
+			return records, false
+		}
+		if err != nil {
+			return nil, true
+		}
+		records = append(records, record)
+	}
+}
+
+// chunkSize returns the size of the chunks of n bytes of data, so that
+// there are about four chunks per worker.
+func (c *CSVParser) chunkSize(n int) int {
+	size := n / (4 * c.workers())
+	if size < minChunkSize {
+		size = minChunkSize
+	}
+
+	return size
+}
+
+// splitChunks splits the data into chunks of at least size bytes. Line
+// breaks outside of quoted fields are preceded by an even number of
+// quotes, as quotes inside of quoted fields are doubled.
+func (c *CSVParser) splitChunks(data []byte, size int) [][]byte {
+	quote := []byte{'"'}
+	if c.swapQuote() {
+		quote = []byte(string(c.Quote))
+	}
+
+	var chunks [][]byte
+	start, counted, quotes := 0, 0, 0
+	for pos := size; pos < len(data); {
+		nl := bytes.IndexByte(data[pos:], '\n')
+		if nl < 0 {
+			break
+		}
+		end := pos + nl + 1
+
+		quotes += bytes.Count(data[counted:end], quote)
+		counted = end
+		if quotes%2 != 0 {
+			// the line break is inside of a quoted field
+			pos = end
+			continue
+		}
+
+		chunks = append(chunks, data[start:end])
+		start, pos = end, end+size
+	}
+
+	return append(chunks, data[start:])
+}
@@ -0,0 +1,81 @@
+package pkg
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseParallel(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser CSVParser
+		in     string
+	}{
+		{
+			name: "quoted line breaks",
+			in: "id,note,qty\n" +
+				"1,\"first\nsecond\",2\n" +
+				"2,\"a \"\"quoted\"\"\nline\n\nbreak\",3\n" +
+				"3,plain,4\n" +
+				"4,\"\"\"\n\"\"\",5\n" +
+				"5,\"x\r\ny\",6\n",
+		},
+		{
+			name:   "other quote",
+			parser: CSVParser{Quote: '\''},
+			in:     "id,note\n1,'it''s\nhere'\n2,\"x\n3,'a\nb'\n",
+		},
+		{
+			name:   "no header",
+			parser: CSVParser{NoHeader: true},
+			in:     "\"a\nb\",1\n\"c\",2\n\"d\ne\nf\",3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seq := tt.parser
+			want, err := seq.parseSequential(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p := tt.parser
+			p.Workers = 3
+			// chunks of every size split the input at every line break,
+			// including the ones inside of quoted fields
+			for size := 1; size <= len(tt.in); size++ {
+				if size == 1 && len(p.splitChunks([]byte(tt.in), size)) < 2 {
+					t.Fatal("expected several chunks")
+				}
+
+				got, err := p.parseParallel([]byte(tt.in), size)
+				if err != nil {
+					t.Fatalf("size %d: %v", size, err)
+				}
+				if !reflect.DeepEqual(got.header, want.header) || !reflect.DeepEqual(got.rows, want.rows) {
+					t.Errorf("size %d: got\n%s\nwant\n%s", size, toCSV(t, got), toCSV(t, want))
+				}
+			}
+		})
+	}
+}
+
+func TestSplitChunks(t *testing.T) {
+	in := "a,b\n1,\"x\ny\"\n2,z\n"
+	chunks := (&CSVParser{}).splitChunks([]byte(in), 1)
+
+	want := []string{"a,b\n", "1,\"x\ny\"\n", "2,z\n", ""}
+	var got []string
+	for _, chunk := range chunks {
+		got = append(got, string(chunk))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !bytes.Equal(bytes.Join(chunks, nil), []byte(in)) {
+		t.Error("chunks do not add up to the input")
+	}
+}
@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// QuoteMode selects which fields a CSVRenderer encloses in quotes.
+type QuoteMode int
+
+const (
+	// QuoteMinimal quotes only fields containing the delimiter, a quote,
+	// a line break or leading white space, as RFC 4180 requires.
+	QuoteMinimal QuoteMode = iota
+	// QuoteAll quotes every field.
+	QuoteAll
+	// QuoteNonNumeric quotes every field which is not a number.
+	QuoteNonNumeric
+	// QuoteNone never quotes fields, rendering fails if a field would
+	// need quotes.
+	QuoteNone
+)
+
+// ParseQuoteMode returns the quote mode with the given name, i.e.
+// "minimal", "all", "nonnumeric" or "none".
+func ParseQuoteMode(name string) (QuoteMode, error) {
+	switch strings.ToLower(name) {
+	case "minimal", "":
+		return QuoteMinimal, nil
+	case "all":
+		return QuoteAll, nil
+	case "nonnumeric", "non-numeric":
+		return QuoteNonNumeric, nil
+	case "none":
+		return QuoteNone, nil
+	}
+
+	return QuoteMinimal, fmt.Errorf("unknown quote mode %q", name)
+}
+
+// CSVRenderer is a renderer implementation that emits RFC 4180 CSV.
+type CSVRenderer struct {
+	// Delimiter is the field delimiter, a comma is used if it is unset.
+	Delimiter rune
+	// Quoting selects which fields are quoted.
+	Quoting QuoteMode
+	// UseCRLF terminates records with \r\n instead of \n.
+	UseCRLF bool
+	// NoHeader omits the header record.
+	NoHeader bool
+}
+
+// Render writes the Content as CSV to the writer.
+func (c *CSVRenderer) Render(content Content, w io.Writer) error {
+	delimiter := c.Delimiter
+	if delimiter == 0 {
+		delimiter = ','
+	}
+	if delimiter == '"' || delimiter == '\r' || delimiter == '\n' || !utf8.ValidRune(delimiter) {
+		return fmt.Errorf("%q is not a valid delimiter", delimiter)
+	}
+
+	newline := "\n"
+	if c.UseCRLF {
+		newline = "\r\n"
+	}
+
+	bw := bufio.NewWriter(w)
+
+	records := content.rows
+	if !c.NoHeader {
+		records = append([][]string{content.header}, records...)
+	}
+
+	for i, record := range records {
+		for j, field := range record {
+			if j > 0 {
+				bw.WriteRune(delimiter)
+			}
+
+			quote, err := c.needsQuotes(field, delimiter, i == 0 && !c.NoHeader)
+			if err != nil {
+				return fmt.Errorf("record %d, field %d: %w", i+1, j+1, err)
+			}
+
+			if quote {
+				bw.WriteString(`"` + strings.ReplaceAll(field, `"`, `""`) + `"`)
+			} else {
+				bw.WriteString(field)
+			}
+		}
+		bw.WriteString(newline)
+	}
+
+	return bw.Flush()
+}
+
+func (c *CSVRenderer) needsQuotes(field string, delimiter rune, header bool) (bool, error) {
+	required := strings.ContainsRune(field, delimiter) ||
+		strings.ContainsAny(field, "\"\r\n") ||
+		strings.IndexFunc(field, unicode.IsSpace) == 0
+
+	switch c.Quoting {
+	case QuoteAll:
+		return true, nil
+	case QuoteNonNumeric:
+		_, numeric := parseNumber(field)
+		return required || header || !numeric, nil
+	case QuoteNone:
+		if required {
+			return false, fmt.Errorf("value %q needs quotes", field)
+		}
+		return false, nil
+	}
+
+	return required, nil
+}
@@ -0,0 +1,54 @@
+package pkg
+
+import "strconv"
+
+// Describe summarizes every column of the Content in one row holding
+// the inferred type, see InferType, the number of non-empty and empty values, the
+// number of distinct values, the minimum, maximum and, for numeric
+// columns, the mean.
+func Describe(c Content) Content {
+	header := []string{"column", "type", "count", "nulls", "distinct", "min", "max", "mean"}
+
+	rows := make([][]string, len(c.header))
+	for i := range c.header {
+		values := c.column(i)
+
+		count, nulls := 0, 0
+		distinct := map[string]struct{}{}
+		for _, v := range values {
+			if v == "" {
+				nulls++
+				continue
+			}
+			count++
+			distinct[v] = struct{}{}
+		}
+
+		typ := InferType(values)
+		name := typ.String()
+		if count == 0 {
+			name = ""
+		}
+
+		mean := ""
+		if typ.Numeric() {
+			mean, _ = avgAgg(values)
+		}
+
+		rows[i] = []string{
+			c.header[i],
+			name,
+			strconv.Itoa(count),
+			strconv.Itoa(nulls),
+			strconv.Itoa(len(distinct)),
+			extremeAgg(values, -1),
+			extremeAgg(values, 1),
+			mean,
+		}
+	}
+
+	return Content{
+		header: header,
+		rows:   rows,
+	}
+}
@@ -0,0 +1,134 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// sniffLen is the number of bytes DetectParser inspects.
+const sniffLen = 4096
+
+var (
+	zipMagic     = []byte("PK\x03\x04")
+	parquetMagic = []byte("PAR1")
+	utf8BOM      = []byte("\xef\xbb\xbf")
+
+	yamlKeyLine = regexp.MustCompile(`^[^,\t;|"]+:(\s|$)`)
+
+	tomlTableLine = regexp.MustCompile(`^\[\[?\s*[A-Za-z_][\w-]*(\s*\.\s*[A-Za-z_][\w-]*)*\s*\]\]?\s*(#.*)?$`)
+	tomlKeyLine   = regexp.MustCompile(`^[A-Za-z_][\w.-]*\s*=\s*("|'|\[|\{|[\w.:+-]+\s*(#.*)?$)`)
+)
+
+// DetectParser inspects the first bytes of the reader and returns a
+// parser for the detected format. The returned reader must be used in
+// place of the original one, as the inspected bytes were consumed from
+// it. CSV is assumed if no other format matches.
+func DetectParser(r io.Reader) (Parser, io.Reader, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, br, err
+	}
+	complete := err == io.EOF
+
+	if bytes.HasPrefix(head, zipMagic) {
+		return registeredParser("xlsx", br)
+	}
+
+	if bytes.HasPrefix(head, parquetMagic) {
+		return registeredParser("parquet", br)
+	}
+
+	text := strings.TrimSpace(string(bytes.TrimPrefix(head, utf8BOM)))
+	lines := sniffLines(text, complete)
+
+	if isTOML(lines) {
+		return &TOMLParser{}, br, nil
+	}
+
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return &JSONParser{}, br, nil
+	}
+
+	if strings.HasPrefix(text, "<") {
+		return &XMLParser{}, br, nil
+	}
+
+	if len(lines) > 1 && strings.HasPrefix(lines[0], "|") && markdownDelimiterRow.MatchString(strings.TrimSpace(lines[1])) {
+		return &MarkdownParser{}, br, nil
+	}
+
+	if isYAML(lines) {
+		return &YAMLParser{}, br, nil
+	}
+
+	return sniffCSV(lines).Parser(), br, nil
+}
+
+// sniffLines splits the sample into its non-empty lines. The last line
+// is dropped if the sample does not cover the whole input, as it is
+// likely cut off.
+func sniffLines(text string, complete bool) []string {
+	lines := strings.Split(text, "\n")
+	if !complete && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+
+	var out []string
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) != "" {
+			out = append(out, line)
+		}
+	}
+
+	return out
+}
+
+// isTOML reports whether the first line which is not a comment is a
+// table header or a key/value pair. Table headers are restricted to bare
+// keys, so that JSON arrays like ["a"] are not mistaken for them.
+func isTOML(lines []string) bool {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		return tomlTableLine.MatchString(line) || tomlKeyLine.MatchString(line)
+	}
+
+	return false
+}
+
+func isYAML(lines []string) bool {
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		return line == "---" ||
+			strings.HasPrefix(line, "--- ") ||
+			strings.HasPrefix(line, "- ") ||
+			yamlKeyLine.MatchString(line)
+	}
+
+	return false
+}
+
+// registeredParser returns the parser of a binary format detected by
+// its magic bytes, which is registered by another package, e.g. by
+// importing pkg/xlsx or pkg/parquet.
+func registeredParser(name string, r io.Reader) (Parser, io.Reader, error) {
+	p, err := NewParser(name)
+	if err != nil {
+		return nil, r, fmt.Errorf("detected %s input: %w", name, err)
+	}
+
+	return p, r, nil
+}
@@ -0,0 +1,115 @@
+package pkg
+
+import "fmt"
+
+// DiffColumn is the name of the column added by Diff, which marks the
+// rows as added, removed or changed.
+const DiffColumn = "diff"
+
+// The markers of the DiffColumn. Rows which are equal on both sides are
+// left unmarked.
+const (
+	DiffAdded   = "+"
+	DiffRemoved = "-"
+	DiffChanged = "~"
+)
+
+// DiffHighlights color the markers of the DiffColumn: added rows green,
+// removed rows red and changed rows yellow.
+var DiffHighlights = []Highlight{
+	{Expression: DiffColumn + ` == "` + DiffAdded + `"`, Color: "green"},
+	{Expression: DiffColumn + ` == "` + DiffRemoved + `"`, Color: "red"},
+	{Expression: DiffColumn + ` == "` + DiffChanged + `"`, Color: "yellow"},
+}
+
+// Diff compares two versions of a table, whose rows are matched by
+// their values in the key column. The result starts with the
+// DiffColumn, followed by the columns of a and those of b which a
+// lacks. Rows are ordered like the rows of a, rows only found in b are
+// appended at the end. Removed rows hold the values of a, added and
+// changed rows those of b, and the changed values are shown as
+// "old → new". Only the columns of both sides are compared. If a key
+// occurs several times, its rows are matched in order.
+func Diff(a, b Content, key string) (Content, error) {
+	ak := a.columnIndex(key)
+	if ak < 0 {
+		return Content{}, fmt.Errorf("column %q does not exist in the first input", key)
+	}
+	bk := b.columnIndex(key)
+	if bk < 0 {
+		return Content{}, fmt.Errorf("column %q does not exist in the second input", key)
+	}
+
+	// the columns of both sides by index in the result
+	header := append([]string{DiffColumn}, a.header...)
+	aColumns := make([]int, len(header))
+	bColumns := make([]int, len(header))
+	for j := range header {
+		aColumns[j], bColumns[j] = j-1, -1
+	}
+	for j, name := range b.header {
+		if i := a.columnIndex(name); i >= 0 {
+			bColumns[i+1] = j
+			continue
+		}
+		header = append(header, name)
+		aColumns = append(aColumns, -1)
+		bColumns = append(bColumns, j)
+	}
+
+	// row returns the values of row i of c, whose columns are given by
+	// index in the result
+	row := func(c Content, i int, columns []int, marker string) []string {
+		out := make([]string, len(header))
+		out[0] = marker
+		for j, column := range columns[1:] {
+			if column >= 0 {
+				out[j+1] = c.At(i, column)
+			}
+		}
+		return out
+	}
+
+	index := map[string][]int{}
+	for i := range b.rows {
+		k := b.At(i, bk)
+		index[k] = append(index[k], i)
+	}
+
+	matched := make([]bool, len(b.rows))
+	var rows [][]string
+	for i := range a.rows {
+		k := a.At(i, ak)
+		matches := index[k]
+		if len(matches) == 0 {
+			rows = append(rows, row(a, i, aColumns, DiffRemoved))
+			continue
+		}
+		index[k] = matches[1:]
+		matched[matches[0]] = true
+
+		before := row(a, i, aColumns, "")
+		after := row(b, matches[0], bColumns, "")
+		for j := 1; j < len(after); j++ {
+			switch {
+			case bColumns[j] < 0:
+				after[j] = before[j]
+			case aColumns[j] >= 0 && before[j] != after[j]:
+				after[0] = DiffChanged
+				after[j] = before[j] + " → " + after[j]
+			}
+		}
+		rows = append(rows, after)
+	}
+
+	for i := range b.rows {
+		if !matched[i] {
+			rows = append(rows, row(b, i, bColumns, DiffAdded))
+		}
+	}
+
+	return Content{
+		header: header,
+		rows:   rows,
+	}, nil
+}
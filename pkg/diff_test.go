@@ -0,0 +1,26 @@
+package pkg
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	a := "id,name,price\n1,apple,10\n2,pear,20\n3,kiwi,30\n"
+	b := "id,name,price,stock\n1,apple,10,5\n2,pear,25,1\n4,fig,40,2\n"
+
+	c, err := Diff(parseCSV(t, a), parseCSV(t, b), "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "diff,id,name,price,stock\n" +
+		",1,apple,10,5\n" +
+		"~,2,pear,20 → 25,1\n" +
+		"-,3,kiwi,30,\n" +
+		"+,4,fig,40,2\n"
+	if got := toCSV(t, c); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := Diff(parseCSV(t, a), parseCSV(t, b), "missing"); err == nil {
+		t.Error("expected an error for a missing key")
+	}
+}
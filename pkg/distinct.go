@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Distinct returns a copy of the Content without duplicate rows, keeping
+// the first of them. Rows are compared by the named columns, or by all
+// columns if none are named. It fails if a column does not exist.
+func Distinct(c Content, columns ...string) (Content, error) {
+	indices := make([]int, len(columns))
+	for i, name := range columns {
+		indices[i] = c.columnIndex(name)
+		if indices[i] < 0 {
+			return Content{}, fmt.Errorf("column %q does not exist", name)
+		}
+	}
+	if len(columns) == 0 {
+		indices = make([]int, len(c.header))
+		for i := range indices {
+			indices[i] = i
+		}
+	}
+
+	kept := map[string]bool{}
+	var rows [][]string
+	for i, row := range c.rows {
+		key := make([]string, len(indices))
+		for j, idx := range indices {
+			key[j] = c.At(i, idx)
+		}
+
+		id := strings.Join(key, "\x00")
+		if !kept[id] {
+			kept[id] = true
+			rows = append(rows, row)
+		}
+	}
+
+	return Content{
+		header: c.header,
+		rows:   rows,
+	}, nil
+}
+
+// DistinctValues returns the distinct values of the named column, in the
+// order they first appear, and the number of rows holding them in a
+// second column named "count".
+func DistinctValues(c Content, column string) (Content, error) {
+	idx := c.columnIndex(column)
+	if idx < 0 {
+		return Content{}, fmt.Errorf("column %q does not exist", column)
+	}
+
+	var values []string
+	counts := map[string]int{}
+	for i := range c.rows {
+		v := c.At(i, idx)
+		if counts[v] == 0 {
+			values = append(values, v)
+		}
+		counts[v]++
+	}
+
+	rows := make([][]string, len(values))
+	for i, v := range values {
+		rows[i] = []string{v, strconv.Itoa(counts[v])}
+	}
+
+	return Content{
+		header: []string{c.header[idx], "count"},
+		rows:   rows,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package pkg
+
+import "testing"
+
+func TestDistinct(t *testing.T) {
+	in := "name,color\napple,red\npear,green\napple,red\napple,green\n"
+
+	tests := []struct {
+		name    string
+		columns []string
+		want    string
+	}{
+		{"all columns", nil, "name,color\napple,red\npear,green\napple,green\n"},
+		{"by column", []string{"name"}, "name,color\napple,red\npear,green\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Distinct(parseCSV(t, in), tt.columns...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := toCSV(t, c); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistinctValues(t *testing.T) {
+	c, err := DistinctValues(parseCSV(t, "name\napple\npear\napple\n"), "name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := toCSV(t, c), "name,count\napple,2\npear,1\n"; got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
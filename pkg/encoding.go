@@ -0,0 +1,184 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// DecodeReader returns a reader of the content of r converted to UTF-8.
+// The charset is a name like "utf-16", "shift_jis", "euc-kr" or
+// "latin1". If it is empty or "auto", the charset is detected from the
+// first bytes: byte order marks, UTF-16 without one, Shift-JIS, EUC-JP
+// and EUC-KR are recognized, and text which is not valid UTF-8 otherwise
+// is taken to be Latin-1 (Windows-1252). Byte order marks are removed
+// in any case. Binary documents like XLSX and Parquet files are
+// returned as they are.
+func DecodeReader(r io.Reader, charset string) (io.Reader, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return br, err
+	}
+	complete := err == io.EOF
+
+	if bytes.HasPrefix(head, zipMagic) || bytes.HasPrefix(head, parquetMagic) {
+		return br, nil
+	}
+
+	var enc encoding.Encoding
+	switch {
+	case charset == "" || strings.EqualFold(charset, "auto"):
+		enc = detectEncoding(head, complete)
+	case strings.EqualFold(charset, "utf-16"):
+		// the byte order is taken from the byte order mark or the zero
+		// bytes, and defaults to little-endian like on Windows
+		var ok bool
+		if enc, ok = detectUTF16(head); !ok {
+			enc = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+		}
+	default:
+		if enc, err = lookupEncoding(charset); err != nil {
+			return br, err
+		}
+	}
+
+	if enc == encoding.Nop && !bytes.HasPrefix(head, utf8BOM) {
+		return br, nil
+	}
+
+	return transform.NewReader(br, unicode.BOMOverride(enc.NewDecoder())), nil
+}
+
+// lookupEncoding returns the encoding of the charset name, which is
+// looked up in the WHATWG and IANA registries.
+func lookupEncoding(charset string) (encoding.Encoding, error) {
+	if enc, err := htmlindex.Get(charset); err == nil {
+		return enc, nil
+	}
+	if enc, err := ianaindex.IANA.Encoding(charset); err == nil && enc != nil {
+		return enc, nil
+	}
+
+	return nil, fmt.Errorf("unknown encoding %q", charset)
+}
+
+// detectEncoding returns the encoding of the sample, which covers the
+// whole input if complete is set. Byte order marks are left to
+// unicode.BOMOverride.
+func detectEncoding(sample []byte, complete bool) encoding.Encoding {
+	if !complete {
+		// the last line is likely cut off in the middle of a character
+		if i := bytes.LastIndexByte(sample, '\n'); i > 0 {
+			sample = sample[:i+1]
+		}
+	}
+
+	if enc, ok := detectUTF16(sample); ok {
+		return enc
+	}
+
+	if validUTF8(sample, complete) {
+		return encoding.Nop
+	}
+
+	// kana and hangul are encoded with different lead bytes, so that
+	// Korean text does not decode to kana as Shift-JIS or EUC-JP, and
+	// Japanese text is recognized before it may decode to hangul
+	if decodesTo(japanese.ShiftJIS, sample, isKana) {
+		return japanese.ShiftJIS
+	}
+	if decodesTo(japanese.EUCJP, sample, isKana) {
+		return japanese.EUCJP
+	}
+	if decodesTo(korean.EUCKR, sample, isHangul) {
+		return korean.EUCKR
+	}
+
+	return charmap.Windows1252
+}
+
+// detectUTF16 recognizes UTF-16 without byte order mark by the zero
+// bytes of ASCII characters, which are the high bytes of the code
+// units.
+func detectUTF16(sample []byte) (encoding.Encoding, bool) {
+	if len(sample) < 4 {
+		return nil, false
+	}
+
+	var even, odd int
+	for i, b := range sample {
+		if b != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			even++
+		} else {
+			odd++
+		}
+	}
+
+	units := len(sample) / 2
+	switch {
+	case odd > units/2 && even == 0:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), true
+	case even > units/2 && odd == 0:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), true
+	}
+
+	return nil, false
+}
+
+// validUTF8 reports whether the sample is valid UTF-8, except for a
+// character cut off at its end if the sample is incomplete.
+func validUTF8(sample []byte, complete bool) bool {
+	if !complete {
+		for i := 0; i < utf8.UTFMax-1 && len(sample) > 0; i++ {
+			if r, _ := utf8.DecodeLastRune(sample); r != utf8.RuneError {
+				break
+			}
+			sample = sample[:len(sample)-1]
+		}
+	}
+
+	return utf8.Valid(sample)
+}
+
+// decodesTo reports whether the sample decodes without errors and to at
+// least one rune the function accepts.
+func decodesTo(enc encoding.Encoding, sample []byte, accept func(rune) bool) bool {
+	text, err := enc.NewDecoder().Bytes(sample)
+	if err != nil || bytes.ContainsRune(text, utf8.RuneError) {
+		return false
+	}
+
+	for _, r := range string(text) {
+		if accept(r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isKana reports whether the rune is a full-width hiragana or katakana.
+func isKana(r rune) bool {
+	return r >= 0x3041 && r <= 0x30ff
+}
+
+// isHangul reports whether the rune is a precomposed hangul syllable.
+func isHangul(r rune) bool {
+	return r >= 0xac00 && r <= 0xd7a3
+}
@@ -0,0 +1,179 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseError describes where a parser failed. Line and Column are
+// 1-based, zero means unknown. Offset is the byte offset of the error
+// in the input, -1 means unknown.
+type ParseError struct {
+	Line    int
+	Column  int
+	Offset  int64
+	Snippet string
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	msg := e.Err.Error()
+
+	switch {
+	case e.Line > 0 && strings.Contains(msg, fmt.Sprintf("line %d", e.Line)):
+		// the wrapped error already names the line
+		return msg
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, msg)
+	case e.Line > 0:
+		return fmt.Sprintf("line %d: %s", e.Line, msg)
+	}
+
+	return msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// maxSnippetLen is the maximum length of ParseError.Snippet in bytes.
+const maxSnippetLen = 120
+
+func snippet(line []byte) string {
+	line = bytes.TrimRight(line, "\r")
+	if len(line) > maxSnippetLen {
+		return string(line[:maxSnippetLen]) + "…"
+	}
+
+	return string(line)
+}
+
+// offsetError creates a ParseError for an error at the byte offset of
+// the complete input data.
+func offsetError(data []byte, offset int64, err error) *ParseError {
+	if offset < 0 || offset > int64(len(data)) {
+		return &ParseError{Offset: -1, Err: err}
+	}
+
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	lineEnd := bytes.IndexByte(data[offset:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(data)
+	} else {
+		lineEnd += int(offset)
+	}
+
+	return &ParseError{
+		Line:    bytes.Count(data[:offset], []byte("\n")) + 1,
+		Column:  int(offset) - lineStart + 1,
+		Offset:  offset,
+		Snippet: snippet(data[lineStart:lineEnd]),
+		Err:     err,
+	}
+}
+
+var errorLine = regexp.MustCompile(`line (\d+)`)
+
+// lineError creates a ParseError for an error whose message mentions
+// the line number, as the errors of the YAML decoder do.
+func lineError(data []byte, err error) error {
+	m := errorLine.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+
+	line, _ := strconv.Atoi(m[1])
+	lines := bytes.Split(data, []byte("\n"))
+	if line < 1 || line > len(lines) {
+		return &ParseError{Line: line, Offset: -1, Err: err}
+	}
+
+	offset := int64(0)
+	for _, l := range lines[:line-1] {
+		offset += int64(len(l)) + 1
+	}
+
+	return &ParseError{
+		Line:    line,
+		Offset:  offset,
+		Snippet: snippet(lines[line-1]),
+		Err:     err,
+	}
+}
+
+// trackLen is the number of most recently read bytes retained by an
+// inputTracker.
+const trackLen = 64 * 1024
+
+// inputTracker retains the tail of the input read through it, so that
+// the line of an error can be recovered without buffering the whole
+// input.
+type inputTracker struct {
+	r     io.Reader
+	buf   []byte
+	start int64 // offset of buf[0] in the input
+	line  int   // line number of buf[0]
+}
+
+func newInputTracker(r io.Reader) *inputTracker {
+	return &inputTracker{r: r, line: 1}
+}
+
+func (t *inputTracker) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	t.buf = append(t.buf, p[:n]...)
+
+	if len(t.buf) > 2*trackLen {
+		drop := len(t.buf) - trackLen
+		t.line += bytes.Count(t.buf[:drop], []byte("\n"))
+		t.start += int64(drop)
+		t.buf = append(t.buf[:0], t.buf[drop:]...)
+	}
+
+	return n, err
+}
+
+// lineAt returns the offset and content of the given line, if it is
+// still retained.
+func (t *inputTracker) lineAt(line int) (int64, []byte, bool) {
+	if line < t.line {
+		return 0, nil, false
+	}
+
+	pos := 0
+	for current := t.line; current < line; current++ {
+		i := bytes.IndexByte(t.buf[pos:], '\n')
+		if i < 0 {
+			return 0, nil, false
+		}
+		pos += i + 1
+	}
+
+	end := bytes.IndexByte(t.buf[pos:], '\n')
+	if end < 0 {
+		end = len(t.buf)
+	} else {
+		end += pos
+	}
+
+	return t.start + int64(pos), t.buf[pos:end], true
+}
+
+// lineColumnError creates a ParseError for an error at the given line
+// and 1-based byte column.
+func (t *inputTracker) lineColumnError(line, column int, err error) *ParseError {
+	e := &ParseError{Line: line, Column: column, Offset: -1, Err: err}
+
+	if offset, text, ok := t.lineAt(line); ok {
+		e.Snippet = snippet(text)
+		if column > 0 {
+			e.Offset = offset + int64(column) - 1
+		}
+	}
+
+	return e
+}
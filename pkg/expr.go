@@ -0,0 +1,474 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// This file implements the small expression language used to filter
+// rows. Expressions refer to the columns of a row by their header name
+// and support the following operators, from lowest to highest
+// precedence:
+//
+//	||
+//	&&
+//	== !=
+//	< <= > >=
+//	+ -
+//	* / %
+//	! - (unary)
+//
+// Literals are numbers, strings quoted with ' or ", true and false.
+// Column names which are not valid identifiers can be quoted with
+// backticks, e.g. `unit price`. Values are compared numerically if both
+// sides are numbers and as strings otherwise.
+
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindNumber
+	kindBool
+)
+
+type value struct {
+	kind valueKind
+	s    string
+	n    float64
+	b    bool
+}
+
+func stringValue(s string) value  { return value{kind: kindString, s: s} }
+func numberValue(n float64) value { return value{kind: kindNumber, n: n} }
+func boolValue(b bool) value      { return value{kind: kindBool, b: b} }
+
+// number reports the numeric value of v, strings are converted if they
+// hold a number.
+func (v value) number() (float64, bool) {
+	switch v.kind {
+	case kindNumber:
+		return v.n, true
+	case kindString:
+		return parseNumber(v.s)
+	}
+
+	return 0, false
+}
+
+func (v value) truthy() bool {
+	switch v.kind {
+	case kindBool:
+		return v.b
+	case kindNumber:
+		return v.n != 0
+	}
+
+	return v.s != ""
+}
+
+func (v value) String() string {
+	switch v.kind {
+	case kindBool:
+		return strconv.FormatBool(v.b)
+	case kindNumber:
+		return strconv.FormatFloat(v.n, 'f', -1, 64)
+	}
+
+	return v.s
+}
+
+// expr is a compiled expression.
+type expr interface {
+	eval(row []string) (value, error)
+}
+
+type literalExpr struct{ v value }
+
+func (e literalExpr) eval([]string) (value, error) { return e.v, nil }
+
+type columnExpr struct {
+	name  string
+	index int
+}
+
+func (e columnExpr) eval(row []string) (value, error) {
+	if e.index >= len(row) {
+		return stringValue(""), nil
+	}
+
+	return stringValue(row[e.index]), nil
+}
+
+type unaryExpr struct {
+	op string
+	x  expr
+}
+
+func (e unaryExpr) eval(row []string) (value, error) {
+	x, err := e.x.eval(row)
+	if err != nil {
+		return value{}, err
+	}
+
+	if e.op == "!" {
+		return boolValue(!x.truthy()), nil
+	}
+
+	n, ok := x.number()
+	if !ok {
+		return value{}, fmt.Errorf("cannot negate %q", x.String())
+	}
+
+	return numberValue(-n), nil
+}
+
+type binaryExpr struct {
+	op   string
+	x, y expr
+}
+
+func (e binaryExpr) eval(row []string) (value, error) {
+	x, err := e.x.eval(row)
+	if err != nil {
+		return value{}, err
+	}
+
+	// && and || short-circuit
+	switch e.op {
+	case "&&":
+		if !x.truthy() {
+			return boolValue(false), nil
+		}
+		y, err := e.y.eval(row)
+		if err != nil {
+			return value{}, err
+		}
+		return boolValue(y.truthy()), nil
+	case "||":
+		if x.truthy() {
+			return boolValue(true), nil
+		}
+		y, err := e.y.eval(row)
+		if err != nil {
+			return value{}, err
+		}
+		return boolValue(y.truthy()), nil
+	}
+
+	y, err := e.y.eval(row)
+	if err != nil {
+		return value{}, err
+	}
+
+	xn, xok := x.number()
+	yn, yok := y.number()
+	numeric := xok && yok && x.kind != kindBool && y.kind != kindBool
+
+	switch e.op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		var cmp int
+		if numeric {
+			cmp = compareFloats(xn, yn)
+		} else {
+			cmp = strings.Compare(x.String(), y.String())
+		}
+		return boolValue(compareResult(e.op, cmp)), nil
+	case "+":
+		if !numeric {
+			return stringValue(x.String() + y.String()), nil
+		}
+		return numberValue(xn + yn), nil
+	}
+
+	if !numeric {
+		return value{}, fmt.Errorf("cannot apply %s to %q and %q", e.op, x.String(), y.String())
+	}
+
+	switch e.op {
+	case "-":
+		return numberValue(xn - yn), nil
+	case "*":
+		return numberValue(xn * yn), nil
+	case "/":
+		if yn == 0 {
+			return value{}, fmt.Errorf("division by zero")
+		}
+		return numberValue(xn / yn), nil
+	case "%":
+		if yn == 0 {
+			return value{}, fmt.Errorf("division by zero")
+		}
+		return numberValue(math.Mod(xn, yn)), nil
+	}
+
+	return value{}, fmt.Errorf("unknown operator %s", e.op)
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func compareResult(op string, cmp int) bool {
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	}
+
+	return cmp >= 0
+}
+
+// compileExpr parses the expression and resolves its column names
+// against the header of the Content.
+func compileExpr(src string, c Content) (expr, error) {
+	tokens, err := lexExpr(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens, content: c}
+	e, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", t.text, t.pos)
+	}
+
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var exprOperators = []string{
+	"||", "&&", "==", "!=", "<=", ">=",
+	"<", ">", "+", "-", "*", "/", "%", "!", "(", ")",
+}
+
+func lexExpr(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"' || r == '`':
+			start := i
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && r != '`' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated quote at offset %d", start)
+			}
+			i++
+
+			kind := tokenString
+			if r == '`' {
+				kind = tokenIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: sb.String(), pos: start})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q at offset %d", r, i)
+			}
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// binaryPrecedence lists the binary operators by increasing precedence.
+var binaryPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+type exprParser struct {
+	tokens  []token
+	pos     int
+	content Content
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+
+	return t
+}
+
+func (p *exprParser) parseBinary(level int) (expr, error) {
+	if level == len(binaryPrecedence) {
+		return p.parseUnary()
+	}
+
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if t.kind != tokenOperator || !containsString(binaryPrecedence[level], t.text) {
+			return x, nil
+		}
+		p.next()
+
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		x = binaryExpr{op: t.text, x: x, y: y}
+	}
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	t := p.peek()
+	if t.kind == tokenOperator && (t.text == "!" || t.text == "-") {
+		p.next()
+
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return unaryExpr{op: t.text, x: x}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", t.text, t.pos)
+		}
+		return literalExpr{numberValue(n)}, nil
+	case tokenString:
+		return literalExpr{stringValue(t.text)}, nil
+	case tokenIdent:
+		if idx := p.content.columnIndex(t.text); idx >= 0 {
+			return columnExpr{name: t.text, index: idx}, nil
+		}
+		switch t.text {
+		case "true":
+			return literalExpr{boolValue(true)}, nil
+		case "false":
+			return literalExpr{boolValue(false)}, nil
+		}
+		return nil, fmt.Errorf("column %q does not exist", t.text)
+	case tokenOperator:
+		if t.text == "(" {
+			x, err := p.parseBinary(0)
+			if err != nil {
+				return nil, err
+			}
+			if closing := p.next(); closing.text != ")" {
+				return nil, fmt.Errorf("expected ) at offset %d", closing.pos)
+			}
+			return x, nil
+		}
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unexpected %q at offset %d", t.text, t.pos)
+}
+
+// exprColumns returns the indices of the columns the expression refers
+// to, in the order they appear.
+func exprColumns(e expr) []int {
+	switch e := e.(type) {
+	case columnExpr:
+		return []int{e.index}
+	case unaryExpr:
+		return exprColumns(e.x)
+	case binaryExpr:
+		return append(exprColumns(e.x), exprColumns(e.y)...)
+	}
+
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
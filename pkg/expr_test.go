@@ -0,0 +1,116 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+	c := NewContent([]string{"name", "price", "qty", "unit price", "code", "note"}, [][]string{
+		{"pear", "2.5", "10", "3", "9"},
+	})
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		// precedence and associativity
+		{"1 + 2 * 3", "7"},
+		{"(1 + 2) * 3", "9"},
+		{"10 - 4 - 3", "3"},
+		{"2 * 3 % 4", "2"},
+		{"-2 * 3", "-6"},
+		{"1 + 1 == 2", "true"},
+		{"1 < 2 == 2 < 3", "true"},
+		{"true || false && false", "true"},
+		{"!false && false", "false"},
+		{"!(price > 1)", "false"},
+
+		// numbers are compared numerically, other values as strings
+		{"price > 10", "false"},
+		{"price < qty", "true"},
+		{"qty == '10.0'", "true"},
+		{"code < 10", "true"},
+		{"name < 'plum'", "true"},
+		{"name == 10", "false"},
+		{"name != 'Pear'", "true"},
+
+		// arithmetic and columns
+		{"price * qty", "25"},
+		{"`unit price` * 2", "6"},
+		{"name + '!'", "pear!"},
+		{"qty / 4", "2.5"},
+		{"qty % 3", "1"},
+		{`"it's" + ' ' + 'a \'b\''`, "it's a 'b'"},
+
+		// values missing in the row are empty
+		{"note == ''", "true"},
+		{"note", ""},
+	}
+
+	for _, tt := range tests {
+		e, err := compileExpr(tt.expr, c)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		v, err := e.eval(c.rows[0])
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if got := v.String(); got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestExprErrors(t *testing.T) {
+	c := NewContent([]string{"name", "price"}, [][]string{{"pear", "2.5"}})
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		// parse errors
+		{"price >", "unexpected end of expression"},
+		{"price > > 1", `unexpected ">" at offset 8`},
+		{"(price > 1", "expected ) at offset 10"},
+		{"name == 'pear", "unterminated quote at offset 8"},
+		{"price # 1", "unexpected '#' at offset 6"},
+		{"price 1", `unexpected "1" at offset 6`},
+		{"1..2", `invalid number "1..2" at offset 0`},
+		{"missing > 1", `column "missing" does not exist`},
+		{"`Unit Price` > 1", `column "Unit Price" does not exist`},
+
+		// evaluation errors
+		{"price / 0", "division by zero"},
+		{"price % (1 - 1)", "division by zero"},
+		{"name * 2", `cannot apply * to "pear" and "2"`},
+		{"-name", `cannot negate "pear"`},
+	}
+
+	for _, tt := range tests {
+		e, err := compileExpr(tt.expr, c)
+		if err == nil {
+			_, err = e.eval(c.rows[0])
+		}
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.expr)
+		} else if err.Error() != tt.want {
+			t.Errorf("%s: got error %q, want %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestExprColumns(t *testing.T) {
+	c := NewContent([]string{"name", "price", "qty"}, nil)
+	e, err := compileExpr("price * qty + -price", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := exprColumns(e), []int{1, 2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Fetcher requests tables from http and https URLs.
+type Fetcher struct {
+	// Client sends the requests, http.DefaultClient is used if it is
+	// unset.
+	Client *http.Client
+	// Header is added to every request.
+	Header http.Header
+	// Token is sent as bearer token in the Authorization header.
+	Token string
+	// Timeout limits the time of a request including reading the
+	// response body. The timeout of the Client is used if it is unset.
+	Timeout time.Duration
+}
+
+// IsURL reports whether the name is an http or https URL.
+func IsURL(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// Open requests the URL and returns the response body, which must be
+// closed by the caller. It also returns the format registered for the
+// Content-Type of the response, or an empty string if it is unknown.
+func (f *Fetcher) Open(url string) (io.ReadCloser, string, error) {
+	return f.OpenContext(context.Background(), url)
+}
+
+// OpenContext is Open, which cancels the request, including reading
+// the response body, once the context is done.
+func (f *Fetcher) OpenContext(ctx context.Context, url string) (io.ReadCloser, string, error) {
+	body, contentType, err := f.open(ctx, url)
+	if err != nil {
+		return nil, "", err
+	}
+
+	format, _ := FormatByMIMEType(contentType)
+
+	return body, format, nil
+}
+
+// open requests the URL and returns the response body and its
+// Content-Type.
+func (f *Fetcher) open(ctx context.Context, url string) (io.ReadCloser, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	for name, values := range f.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if f.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+f.Token)
+	}
+
+	client := http.DefaultClient
+	if f.Client != nil {
+		client = f.Client
+	}
+	if f.Timeout > 0 {
+		c := *client
+		c.Timeout = f.Timeout
+		client = &c
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, "", fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	return resp.Body, resp.Header.Get("Content-Type"), nil
+}
+
+// Fetch requests the URL and parses the response body. The parser is
+// selected by the Content-Type of the response, and detected from the
+// body if there is no parser for it, see DetectParser. The body is
+// decoded from the charset of the Content-Type, if it has one.
+func (f *Fetcher) Fetch(url string) (Content, error) {
+	return f.FetchContext(context.Background(), url)
+}
+
+// FetchContext is Fetch, which stops requesting and parsing once the
+// context is done.
+func (f *Fetcher) FetchContext(ctx context.Context, url string) (Content, error) {
+	body, contentType, err := f.open(ctx, url)
+	if err != nil {
+		return Content{}, err
+	}
+	defer body.Close()
+
+	parser, r, err := responseParser(contentType, "", &contextReader{ctx: ctx, r: body})
+	if err != nil {
+		return Content{}, contextError(ctx, err)
+	}
+
+	c, err := parser.Parse(r)
+	return c, contextError(ctx, err)
+}
+
+// responseParser returns the parser of the Content-Type of a response,
+// or the one detected from the body if there is no parser for it. The
+// body is decoded from the charset, or else the charset of the
+// Content-Type, before it is detected. The returned reader must be used
+// in place of the body, like for DetectParser.
+func responseParser(contentType, charset string, body io.Reader) (Parser, io.Reader, error) {
+	if charset == "" {
+		_, params, _ := mime.ParseMediaType(contentType)
+		charset = params["charset"]
+	}
+	if charset != "" {
+		var err error
+		if body, err = DecodeReader(body, charset); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	format, _ := FormatByMIMEType(contentType)
+	if parser, err := NewParser(format); err == nil {
+		return parser, body, nil
+	}
+
+	return DetectParser(body)
+}
+
+// FetchAndFormat requests the URL using the Fetcher, or a zero Fetcher
+// if it is nil, and writes the parsed response body to the writer, in
+// the same way as Format does, including the options of the parser like
+// WithEncoding and WithNullString. The body is decoded before its
+// format is detected, from the charset of WithEncoding or else of the
+// Content-Type of the response.
+func FetchAndFormat(f *Fetcher, url string, w io.Writer, opts ...Option) error {
+	if f == nil {
+		f = &Fetcher{}
+	}
+
+	body, contentType, err := f.open(context.Background(), url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	parser, r, err := responseParser(contentType, newOptions(opts).charset, body)
+	if err != nil {
+		return err
+	}
+
+	// the body is decoded already
+	return Format(parser, r, w, append(opts, WithEncoding(""))...)
+}
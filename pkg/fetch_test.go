@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestFetchAndFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []Option
+		want        string
+	}{
+		{
+			name:        "csv",
+			contentType: "text/csv",
+			body:        fruitsCSV,
+			want:        fruitsCSV,
+		},
+		{
+			name: "detected",
+			body: `[{"name": "apple", "price": 1.5}, {"name": "pear", "price": 2}]`,
+			want: fruitsCSV,
+		},
+		{
+			name:        "encoding",
+			contentType: "text/csv",
+			body:        "name\ncaf\xe9\n",
+			opts:        []Option{WithEncoding("latin1")},
+			want:        "name\ncafé\n",
+		},
+		{
+			name: "detected utf-16",
+			body: utf16le(`[{"name": "apple", "price": 1.5}, {"name": "pear", "price": 2}]`),
+			opts: []Option{WithEncoding("utf-16")},
+			want: fruitsCSV,
+		},
+		{
+			name:        "charset",
+			contentType: "text/csv; charset=iso-8859-1",
+			body:        "name\ncaf\xe9\n",
+			want:        "name\ncafé\n",
+		},
+		{
+			name:        "detected charset",
+			contentType: "text/plain; charset=utf-16le",
+			body:        utf16le(`[{"name": "café"}]`),
+			want:        "name\ncafé\n",
+		},
+		{
+			name:        "encoding over charset",
+			contentType: "text/csv; charset=utf-8",
+			body:        "name\ncaf\xe9\n",
+			opts:        []Option{WithEncoding("latin1")},
+			want:        "name\ncafé\n",
+		},
+		{
+			name:        "null string",
+			contentType: "application/json",
+			body:        `[{"name": "apple", "price": null}]`,
+			opts:        []Option{WithNullString("-")},
+			want:        "name,price\napple,-\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			var b bytes.Buffer
+			opts := append([]Option{WithRenderer(&CSVRenderer{})}, tt.opts...)
+			if err := FetchAndFormat(nil, server.URL, &b, opts...); err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// utf16le returns the text encoded as UTF-16 in little-endian byte
+// order, without a byte order mark.
+func utf16le(s string) string {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(u), byte(u>>8))
+	}
+
+	return string(b)
+}
@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// minFitWidth is the width below which columns are not shrunk to fit
+// the table into its width, further columns are hidden instead.
+const minFitWidth = 8
+
+// TerminalWidth returns the width of the terminal w writes to. The
+// COLUMNS environment variable takes precedence, 0 is returned if the
+// width is unknown.
+func TerminalWidth(w io.Writer) int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+
+	f, ok := w.(fileDescriptor)
+	if !ok {
+		return 0
+	}
+
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+
+	return width
+}
+
+// cellWidth returns the display width of the widest line of the value.
+func cellWidth(value string) int {
+	width := 0
+	for _, line := range strings.Split(value, "\n") {
+		if w := displayWidth(line); w > width {
+			width = w
+		}
+	}
+
+	return width
+}
+
+// fitWidths computes the widths of the columns, so that a table with
+// borders and padding is no wider than width. The widest columns are
+// shrunk first, down to minFitWidth. If that is not enough, the last
+// columns are dropped. It returns the number of columns to keep and
+// their widths.
+func fitWidths(widths []int, width int) (int, []int) {
+	tableWidth := func(widths []int) int {
+		total := 3*len(widths) + 1
+		for _, w := range widths {
+			total += w
+		}
+		return total
+	}
+
+	fitted := append([]int(nil), widths...)
+	for i, w := range fitted {
+		if w > minFitWidth {
+			fitted[i] = minFitWidth
+		}
+	}
+
+	keep := len(widths)
+	for keep > 1 && tableWidth(fitted[:keep]) > width {
+		keep--
+	}
+
+	fitted = append(fitted[:0:0], widths[:keep]...)
+	for tableWidth(fitted) > width {
+		widest := 0
+		for i, w := range fitted {
+			if w > fitted[widest] {
+				widest = i
+			}
+		}
+		if fitted[widest] <= minFitWidth {
+			break
+		}
+		fitted[widest]--
+	}
+
+	return keep, fitted
+}
+
+// writeHiddenColumns notes the columns dropped to fit the table.
+func writeHiddenColumns(w io.Writer, hidden []string) error {
+	if len(hidden) == 0 {
+		return nil
+	}
+
+	noun := "columns"
+	if len(hidden) == 1 {
+		noun = "column"
+	}
+	_, err := fmt.Fprintf(w, "\n%d more %s: %s\n", len(hidden), noun, strings.Join(hidden, ", "))
+
+	return err
+}
@@ -0,0 +1,156 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// FixedWidthParser is a parser implementation that parses text aligned
+// in columns, like mainframe reports or the output of ps and df. The
+// first line is used as header.
+type FixedWidthParser struct {
+	// Widths are the widths of the columns in characters, the last
+	// column extends to the end of the line. If it is unset, the columns
+	// are inferred from the character positions which are blank in every
+	// line.
+	Widths []int
+}
+
+// fixedWidthTab is the tab stop used to expand tabs.
+const fixedWidthTab = 8
+
+// Parse converts the content of a reader to the Content representation.
+func (f *FixedWidthParser) Parse(reader io.Reader) (Content, error) {
+	s := bufio.NewScanner(reader)
+	s.Buffer(nil, 1024*1024)
+
+	var lines [][]rune
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), " \t\r")
+		if line == "" {
+			continue
+		}
+		lines = append(lines, expandTabs(line))
+	}
+	if err := s.Err(); err != nil {
+		return Content{}, err
+	}
+
+	if len(lines) == 0 {
+		return Content{}, fmt.Errorf("input is empty")
+	}
+
+	var starts []int
+	if len(f.Widths) > 0 {
+		start := 0
+		for _, width := range f.Widths {
+			if width <= 0 {
+				return Content{}, fmt.Errorf("column widths must be positive")
+			}
+			starts = append(starts, start)
+			start += width
+		}
+	} else {
+		starts = inferColumnStarts(lines)
+	}
+
+	rows := make([][]string, len(lines))
+	for i, line := range lines {
+		row := make([]string, len(starts))
+		for j, start := range starts {
+			end := len(line)
+			if j+1 < len(starts) && starts[j+1] < end {
+				end = starts[j+1]
+			}
+			if start < end {
+				row[j] = strings.TrimSpace(string(line[start:end]))
+			}
+		}
+		rows[i] = row
+	}
+
+	return Content{
+		header: rows[0],
+		rows:   rows[1:],
+	}, nil
+}
+
+// inferColumnStarts returns the positions at which a column starts,
+// i.e. non-blank positions following a position which is blank in all
+// lines.
+func inferColumnStarts(lines [][]rune) []int {
+	width := 0
+	for _, line := range lines {
+		if len(line) > width {
+			width = len(line)
+		}
+	}
+
+	blank := make([]bool, width)
+	for i := range blank {
+		blank[i] = true
+	}
+	for _, line := range lines {
+		for i, r := range line {
+			if r != ' ' {
+				blank[i] = false
+			}
+		}
+	}
+
+	var starts []int
+	for i := 0; i < width; i++ {
+		if !blank[i] && (i == 0 || blank[i-1]) {
+			starts = append(starts, i)
+		}
+	}
+
+	if len(starts) == 0 {
+		return []int{0}
+	}
+
+	// Blank leading positions belong to the first column.
+	starts[0] = 0
+
+	// Columns without a header, e.g. words of free text in the last
+	// column, are merged into the column to their left.
+	header := lines[0]
+	merged := starts[:1]
+	for j, start := range starts[1:] {
+		end := width
+		if j+2 < len(starts) {
+			end = starts[j+2]
+		}
+		if start < len(header) && strings.TrimSpace(string(header[start:minInt(end, len(header))])) != "" {
+			merged = append(merged, start)
+		}
+	}
+
+	return merged
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func expandTabs(line string) []rune {
+	var out []rune
+	for _, r := range line {
+		if r == '\t' {
+			out = append(out, ' ')
+			for len(out)%fixedWidthTab != 0 {
+				out = append(out, ' ')
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+
+	return out
+}
@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// flattenRows replaces nested objects and arrays of every row by
+// dot-notation keys, e.g. {"user":{"name":"a"}} becomes {"user.name":"a"}
+// and {"tags":["a","b"]} becomes {"tags.0":"a","tags.1":"b"}. Values
+// nested deeper than maxDepth are kept as they are, a maxDepth of zero
+// or less flattens all levels.
+func flattenRows(rows []map[string]interface{}, maxDepth int) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		flat := map[string]interface{}{}
+		for k, v := range row {
+			flattenValue(k, v, 1, maxDepth, flat)
+		}
+		out[i] = flat
+	}
+
+	return out
+}
+
+func flattenValue(key string, v interface{}, depth, maxDepth int, out map[string]interface{}) {
+	if maxDepth > 0 && depth > maxDepth {
+		out[key] = v
+		return
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			out[key] = v
+			return
+		}
+		for k, nested := range v {
+			flattenValue(key+"."+k, nested, depth+1, maxDepth, out)
+		}
+	case map[interface{}]interface{}:
+		if len(v) == 0 {
+			out[key] = v
+			return
+		}
+		for k, nested := range v {
+			flattenValue(key+"."+fmt.Sprint(k), nested, depth+1, maxDepth, out)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			out[key] = v
+			return
+		}
+		for i, nested := range v {
+			flattenValue(key+"."+strconv.Itoa(i), nested, depth+1, maxDepth, out)
+		}
+	case []map[string]interface{}:
+		if len(v) == 0 {
+			out[key] = v
+			return
+		}
+		for i, nested := range v {
+			flattenValue(key+"."+strconv.Itoa(i), nested, depth+1, maxDepth, out)
+		}
+	default:
+		out[key] = v
+	}
+}
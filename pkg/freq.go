@@ -0,0 +1,70 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Frequencies counts the rows by their values of the named columns. The
+// result holds the distinct values, the number of rows holding them in
+// a column named "count" and their share of all rows in a column named
+// "percent", rounded to one decimal. The most frequent values come
+// first, values of equal frequency in the order they first appear. It
+// fails if no column is named or a column does not exist.
+func Frequencies(c Content, columns ...string) (Content, error) {
+	if len(columns) == 0 {
+		return Content{}, fmt.Errorf("no column to count the values of")
+	}
+
+	indices := make([]int, len(columns))
+	header := make([]string, 0, len(columns)+2)
+	for i, name := range columns {
+		indices[i] = c.columnIndex(name)
+		if indices[i] < 0 {
+			return Content{}, fmt.Errorf("column %q does not exist", name)
+		}
+		header = append(header, c.header[indices[i]])
+	}
+	header = append(header, "count", "percent")
+
+	type value struct {
+		key   []string
+		count int
+	}
+
+	var values []*value
+	byKey := map[string]*value{}
+	for i := range c.rows {
+		key := make([]string, len(indices))
+		for j, idx := range indices {
+			key[j] = c.At(i, idx)
+		}
+
+		id := strings.Join(key, "\x00")
+		v, ok := byKey[id]
+		if !ok {
+			v = &value{key: key}
+			byKey[id] = v
+			values = append(values, v)
+		}
+		v.count++
+	}
+
+	sort.SliceStable(values, func(a, b int) bool {
+		return values[a].count > values[b].count
+	})
+
+	rows := make([][]string, len(values))
+	for i, v := range values {
+		percent := math.Round(float64(v.count)*1000/float64(len(c.rows))) / 10
+		rows[i] = append(append([]string{}, v.key...), strconv.Itoa(v.count), formatNumber(percent))
+	}
+
+	return Content{
+		header: header,
+		rows:   rows,
+	}, nil
+}
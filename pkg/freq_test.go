@@ -0,0 +1,25 @@
+package pkg
+
+import "testing"
+
+func TestFrequencies(t *testing.T) {
+	in := "status,method\n200,GET\n500,GET\n200,POST\n404,GET\n200,GET\n"
+
+	tests := []struct {
+		columns []string
+		want    string
+	}{
+		{[]string{"status"}, "status,count,percent\n200,3,60\n500,1,20\n404,1,20\n"},
+		{[]string{"method", "status"}, "method,status,count,percent\nGET,200,2,40\nGET,500,1,20\nPOST,200,1,20\nGET,404,1,20\n"},
+	}
+
+	for _, tt := range tests {
+		c, err := Frequencies(parseCSV(t, in), tt.columns...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := toCSV(t, c); got != tt.want {
+			t.Errorf("%v: got\n%s\nwant\n%s", tt.columns, got, tt.want)
+		}
+	}
+}
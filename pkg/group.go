@@ -0,0 +1,288 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AggFunc aggregates the values of a column into a single value.
+type AggFunc func(values []string) (string, error)
+
+// Predefined aggregate functions. Empty values are ignored by all of
+// them, and all but Count return an empty value if there are no others.
+// Sum and Avg fail on values which are not numbers.
+var (
+	Sum   AggFunc = sumAgg
+	Avg   AggFunc = avgAgg
+	Min   AggFunc = minAgg
+	Max   AggFunc = maxAgg
+	Count AggFunc = countAgg
+)
+
+// ParseAggFunc returns the aggregate function with the given name, one
+// of sum, avg, min, max or count.
+func ParseAggFunc(name string) (AggFunc, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "sum":
+		return Sum, nil
+	case "avg", "mean":
+		return Avg, nil
+	case "min":
+		return Min, nil
+	case "max":
+		return Max, nil
+	case "count":
+		return Count, nil
+	}
+
+	return nil, fmt.Errorf("%q is not a supported aggregate function", name)
+}
+
+// GroupBy returns a Transform which groups the rows by the key columns
+// and aggregates the columns in aggs per group. The result contains the
+// key columns followed by the aggregated columns in their original
+// order, groups are listed in the order they first appear. Key columns
+// cannot be aggregated.
+func GroupBy(keys []string, aggs map[string]AggFunc) Transform {
+	return func(c Content) (Content, error) {
+		keyIndices := make([]int, len(keys))
+		for i, name := range keys {
+			keyIndices[i] = c.columnIndex(name)
+			if keyIndices[i] < 0 {
+				return Content{}, fmt.Errorf("column %q does not exist", name)
+			}
+		}
+
+		aggIndices, aggFuncs, err := resolveAggs(c, aggs)
+		if err != nil {
+			return Content{}, err
+		}
+		for _, idx := range aggIndices {
+			for _, key := range keyIndices {
+				if idx == key {
+					return Content{}, fmt.Errorf("column %q is grouped by and cannot be aggregated", c.header[idx])
+				}
+			}
+		}
+
+		header := make([]string, 0, len(keyIndices)+len(aggIndices))
+		for _, idx := range keyIndices {
+			header = append(header, c.header[idx])
+		}
+		for _, idx := range aggIndices {
+			header = append(header, c.header[idx])
+		}
+
+		type group struct {
+			key    []string
+			values [][]string
+		}
+
+		var order []*group
+		groups := map[string]*group{}
+		for i := range c.rows {
+			if err := c.canceled(i); err != nil {
+				return Content{}, err
+			}
+			key := make([]string, len(keyIndices))
+			for j, idx := range keyIndices {
+				key[j] = c.At(i, idx)
+			}
+
+			id := strings.Join(key, "\x00")
+			g, ok := groups[id]
+			if !ok {
+				g = &group{key: key, values: make([][]string, len(aggIndices))}
+				groups[id] = g
+				order = append(order, g)
+			}
+
+			for j, idx := range aggIndices {
+				g.values[j] = append(g.values[j], c.At(i, idx))
+			}
+		}
+
+		rows := make([][]string, 0, len(order))
+		for _, g := range order {
+			row := append([]string{}, g.key...)
+			for j, fn := range aggFuncs {
+				v, err := fn(g.values[j])
+				if err != nil {
+					return Content{}, fmt.Errorf("column %q: %w", header[len(keyIndices)+j], err)
+				}
+				row = append(row, v)
+			}
+			rows = append(rows, row)
+		}
+
+		return Content{
+			header: header,
+			rows:   rows,
+		}, nil
+	}
+}
+
+// resolveAggs returns the column indices and functions of aggs in the
+// order of the columns of the Content.
+func resolveAggs(c Content, aggs map[string]AggFunc) ([]int, []AggFunc, error) {
+	byIndex := map[int]AggFunc{}
+	for name, fn := range aggs {
+		idx := c.columnIndex(name)
+		if idx < 0 {
+			return nil, nil, fmt.Errorf("column %q does not exist", name)
+		}
+		byIndex[idx] = fn
+	}
+
+	var indices []int
+	var funcs []AggFunc
+	for i := range c.header {
+		if fn, ok := byIndex[i]; ok {
+			indices = append(indices, i)
+			funcs = append(funcs, fn)
+		}
+	}
+
+	return indices, funcs, nil
+}
+
+func numbers(values []string) ([]float64, error) {
+	var out []float64
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+
+		n, ok := parseNumber(v)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a number", v)
+		}
+		out = append(out, n)
+	}
+
+	return out, nil
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func sumAgg(values []string) (string, error) {
+	ns, err := numbers(values)
+	if err != nil || len(ns) == 0 {
+		return "", err
+	}
+
+	sum := 0.0
+	for _, n := range ns {
+		sum += n
+	}
+
+	return formatNumber(sum), nil
+}
+
+func avgAgg(values []string) (string, error) {
+	ns, err := numbers(values)
+	if err != nil || len(ns) == 0 {
+		return "", err
+	}
+
+	sum := 0.0
+	for _, n := range ns {
+		sum += n
+	}
+
+	return formatNumber(sum / float64(len(ns))), nil
+}
+
+func minAgg(values []string) (string, error) {
+	return extremeAgg(values, -1), nil
+}
+
+func maxAgg(values []string) (string, error) {
+	return extremeAgg(values, 1), nil
+}
+
+// extremeAgg returns the smallest (sign -1) or largest (sign 1) value,
+// comparing numbers and dates by value.
+func extremeAgg(values []string, sign int) string {
+	kind := detectSortKind(values)
+
+	out := ""
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if out == "" || compareValues(v, out, kind)*sign > 0 {
+			out = v
+		}
+	}
+
+	return out
+}
+
+func countAgg(values []string) (string, error) {
+	count := 0
+	for _, v := range values {
+		if v != "" {
+			count++
+		}
+	}
+
+	return strconv.Itoa(count), nil
+}
+
+// TopN returns a Transform which keeps the first n rows of every group
+// of rows with equal values in the group columns, e.g. the slowest
+// three requests per endpoint with TopN(3, []string{"endpoint"},
+// "duration desc"). The rows are sorted by the spec like SortBy first,
+// unless it is empty, so that the order of a preceding sort is kept.
+// Groups are listed in the order their first rows appear, all rows form
+// a single group if no columns are named.
+func TopN(n int, groups []string, spec string) Transform {
+	return func(c Content) (Content, error) {
+		indices := make([]int, len(groups))
+		for i, name := range groups {
+			indices[i] = c.columnIndex(name)
+			if indices[i] < 0 {
+				return Content{}, fmt.Errorf("column %q does not exist", name)
+			}
+		}
+
+		if spec != "" {
+			var err error
+			if c, err = SortBy(spec)(c); err != nil {
+				return Content{}, err
+			}
+		}
+
+		var order []string
+		top := map[string][][]string{}
+		for i, row := range c.rows {
+			key := make([]string, len(indices))
+			for j, idx := range indices {
+				key[j] = c.At(i, idx)
+			}
+
+			id := strings.Join(key, "\x00")
+			rows, ok := top[id]
+			if !ok {
+				order = append(order, id)
+			}
+			if len(rows) < n {
+				top[id] = append(rows, row)
+			}
+		}
+
+		var rows [][]string
+		for _, id := range order {
+			rows = append(rows, top[id]...)
+		}
+
+		return Content{
+			header: c.header,
+			rows:   rows,
+		}, nil
+	}
+}
@@ -0,0 +1,196 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Highlight colors the cells of the rows matching an expression, e.g.
+// to show failed checks in red. Highlights are applied by the
+// TableRenderer, if it prints colors, and by the HTMLRenderer.
+type Highlight struct {
+	// Expression selects the rows, in the syntax of Filter, e.g.
+	// `status == "FAIL"` or "latency > 500". Rows whose values cannot be
+	// evaluated, e.g. because a value is not a number, do not match.
+	Expression string
+	// Color is the name of the color, e.g. "red", or of several colors
+	// joined by "+", e.g. "bold+red" or "black+bg-yellow", see
+	// HighlightColors.
+	Color string
+	// Row colors all cells of the matching rows. Otherwise only the
+	// cells of the columns the expression refers to are colored, or all
+	// cells if it refers to none.
+	Row bool
+}
+
+// highlightColor is a color by its SGR parameter and CSS declaration.
+type highlightColor struct {
+	name string
+	sgr  int
+	css  string
+}
+
+var highlightColors = []highlightColor{
+	{"bold", 1, "font-weight: bold"},
+	{"dim", 2, "opacity: 0.6"},
+	{"italic", 3, "font-style: italic"},
+	{"underline", 4, "text-decoration: underline"},
+	{"black", 30, "color: #000"},
+	{"red", 31, "color: #c00"},
+	{"green", 32, "color: #080"},
+	{"yellow", 33, "color: #b80"},
+	{"blue", 34, "color: #00c"},
+	{"magenta", 35, "color: #c0c"},
+	{"cyan", 36, "color: #0aa"},
+	{"white", 37, "color: #fff"},
+	{"gray", 90, "color: #888"},
+	{"bg-black", 40, "background-color: #000"},
+	{"bg-red", 41, "background-color: #fcc"},
+	{"bg-green", 42, "background-color: #cfc"},
+	{"bg-yellow", 43, "background-color: #ffc"},
+	{"bg-blue", 44, "background-color: #ccf"},
+	{"bg-magenta", 45, "background-color: #fcf"},
+	{"bg-cyan", 46, "background-color: #cff"},
+	{"bg-white", 47, "background-color: #fff"},
+	{"bg-gray", 100, "background-color: #ddd"},
+}
+
+// HighlightColors returns the names of the colors of a Highlight.
+func HighlightColors() []string {
+	names := make([]string, len(highlightColors))
+	for i, color := range highlightColors {
+		names[i] = color.name
+	}
+
+	return names
+}
+
+// ParseHighlight parses a highlight like `status == "FAIL":red`, i.e.
+// an expression and a color separated by the last colon. A trailing
+// ":row" colors the whole row, e.g. "latency > 500:yellow:row".
+func ParseHighlight(spec string) (Highlight, error) {
+	expression, color, ok := cutLast(spec, ":")
+	if !ok {
+		return Highlight{}, fmt.Errorf("%q is not a valid highlight, expected expression:color", spec)
+	}
+
+	var row bool
+	if strings.EqualFold(strings.TrimSpace(color), "row") {
+		row = true
+		if expression, color, ok = cutLast(expression, ":"); !ok {
+			return Highlight{}, fmt.Errorf("%q is not a valid highlight, expected expression:color:row", spec)
+		}
+	}
+	if strings.TrimSpace(expression) == "" {
+		return Highlight{}, fmt.Errorf("%q is not a valid highlight, the expression is empty", spec)
+	}
+
+	h := Highlight{
+		Expression: expression,
+		Color:      strings.ToLower(strings.TrimSpace(color)),
+		Row:        row,
+	}
+	if _, err := parseHighlightColor(h.Color); err != nil {
+		return Highlight{}, err
+	}
+
+	return h, nil
+}
+
+// cutLast slices s around the last instance of sep.
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+
+	return s, "", false
+}
+
+// parseHighlightColor returns the colors of a name like "bold+red".
+func parseHighlightColor(name string) ([]highlightColor, error) {
+	var colors []highlightColor
+	for _, part := range strings.Split(name, "+") {
+		found := false
+		for _, color := range highlightColors {
+			if strings.EqualFold(strings.TrimSpace(part), color.name) {
+				colors = append(colors, color)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown color %q, supported colors: %s", part, strings.Join(HighlightColors(), ", "))
+		}
+	}
+
+	return colors, nil
+}
+
+// highlightCells evaluates the highlights for the rows of the Content.
+// It returns the colors of every cell, later highlights taking
+// precedence, or nil if no highlights are given.
+func highlightCells(c Content, highlights []Highlight) ([][][]highlightColor, error) {
+	if len(highlights) == 0 {
+		return nil, nil
+	}
+
+	cells := make([][][]highlightColor, len(c.rows))
+	for i := range cells {
+		cells[i] = make([][]highlightColor, len(c.header))
+	}
+
+	for _, h := range highlights {
+		e, err := compileExpr(h.Expression, c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid highlight %q: %w", h.Expression, err)
+		}
+		colors, err := parseHighlightColor(h.Color)
+		if err != nil {
+			return nil, err
+		}
+
+		columns := exprColumns(e)
+		if h.Row || len(columns) == 0 {
+			columns = make([]int, len(c.header))
+			for j := range columns {
+				columns[j] = j
+			}
+		}
+
+		for i, row := range c.rows {
+			if v, err := e.eval(row); err != nil || !v.truthy() {
+				continue
+			}
+			for _, j := range columns {
+				cells[i][j] = append(cells[i][j], colors...)
+			}
+		}
+	}
+
+	return cells, nil
+}
+
+// highlightSGR returns the SGR parameters of the colors.
+func highlightSGR(colors []highlightColor) []int {
+	sgr := make([]int, len(colors))
+	for i, color := range colors {
+		sgr[i] = color.sgr
+	}
+
+	return sgr
+}
+
+// highlightStyle returns the style attribute of the colors, or "" if
+// there are none.
+func highlightStyle(colors []highlightColor) string {
+	if len(colors) == 0 {
+		return ""
+	}
+
+	declarations := make([]string, len(colors))
+	for i, color := range colors {
+		declarations[i] = color.css
+	}
+
+	return ` style="` + strings.Join(declarations, "; ") + `"`
+}
@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"bufio"
+	"html"
+	"io"
+)
+
+// HTMLRenderer is a renderer implementation that emits an HTML table.
+// The optional class fields are set as class attributes so the table
+// can be styled by the embedding document.
+type HTMLRenderer struct {
+	// TableClass is the class attribute of the <table> element.
+	TableClass string
+	// HeaderClass is the class attribute of the <thead> element.
+	HeaderClass string
+	// RowClass is the class attribute of every <tr> element in <tbody>.
+	RowClass string
+	// Title is written as <caption> of the table. If Caption is set,
+	// the table is wrapped in a <figure> with the Caption as
+	// <figcaption>.
+	Title, Caption string
+	// Highlights color the cells of matching rows with style
+	// attributes.
+	Highlights []Highlight
+}
+
+// Render writes the Content as an HTML table to the writer.
+func (h *HTMLRenderer) Render(c Content, w io.Writer) error {
+	highlighted, err := highlightCells(c, h.Highlights)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+
+	if h.Caption != "" {
+		bw.WriteString("<figure>\n")
+	}
+	bw.WriteString("<table" + htmlClass(h.TableClass) + ">\n")
+	if h.Title != "" {
+		bw.WriteString("  <caption>" + html.EscapeString(h.Title) + "</caption>\n")
+	}
+	bw.WriteString("  <thead" + htmlClass(h.HeaderClass) + ">\n")
+	bw.WriteString("    <tr>\n")
+	for _, value := range c.header {
+		bw.WriteString("      <th>" + html.EscapeString(value) + "</th>\n")
+	}
+	bw.WriteString("    </tr>\n")
+	bw.WriteString("  </thead>\n")
+
+	bw.WriteString("  <tbody>\n")
+	for i, row := range c.rows {
+		bw.WriteString("    <tr" + htmlClass(h.RowClass) + ">\n")
+		for j, value := range row {
+			style := ""
+			if highlighted != nil && j < len(highlighted[i]) {
+				style = highlightStyle(highlighted[i][j])
+			}
+			bw.WriteString("      <td" + style + ">" + html.EscapeString(value) + "</td>\n")
+		}
+		bw.WriteString("    </tr>\n")
+	}
+	bw.WriteString("  </tbody>\n")
+	bw.WriteString("</table>\n")
+	if h.Caption != "" {
+		bw.WriteString("<figcaption>" + html.EscapeString(h.Caption) + "</figcaption>\n")
+		bw.WriteString("</figure>\n")
+	}
+
+	return bw.Flush()
+}
+
+func htmlClass(class string) string {
+	if class == "" {
+		return ""
+	}
+
+	return ` class="` + html.EscapeString(class) + `"`
+}
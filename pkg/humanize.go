@@ -0,0 +1,179 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	iecByteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siByteUnits  = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// durationUnits are the units durations of a minute and longer are
+// split into, by their length in seconds.
+var durationUnits = []struct {
+	name    string
+	seconds float64
+}{
+	{"d", 86400},
+	{"h", 3600},
+	{"m", 60},
+	{"s", 1},
+}
+
+// shortDurationUnits are the units durations of less than a minute are
+// printed in, by their number per second.
+var shortDurationUnits = []struct {
+	name  string
+	scale float64
+}{
+	{"s", 1},
+	{"ms", 1e3},
+	{"µs", 1e6},
+	{"ns", 1e9},
+}
+
+// durationParts is the number of units durations of a minute and longer
+// are printed with, e.g. "1d2h3m".
+const durationParts = 3
+
+// HumanizeBytes returns a Transform which prints the numbers of the
+// named columns as sizes in bytes with binary units, e.g. "1.5 KiB" for
+// 1536, or with decimal units, e.g. "1.5 kB" for 1500, if si is set.
+// Sizes below ten units keep one decimal. Values which are not numbers
+// are kept as they are. It fails if a column does not exist.
+func HumanizeBytes(columns []string, si bool) Transform {
+	return humanizeColumns(columns, func(n float64) string {
+		return formatBytes(n, si)
+	})
+}
+
+// HumanizeDurations returns a Transform which prints the numbers of the
+// named columns, which count the unit, e.g. time.Second, as durations.
+// Durations of a minute and longer are split into days, hours, minutes
+// and seconds, of which the three largest are printed, e.g. "1d2h3m"
+// for 93784 seconds. Shorter ones are printed in the largest unit which
+// fits, e.g. "1.5s" or "250ms". Values which are not numbers are kept
+// as they are. It fails if a column does not exist.
+func HumanizeDurations(columns []string, unit time.Duration) Transform {
+	return humanizeColumns(columns, func(n float64) string {
+		return formatDuration(n * unit.Seconds())
+	})
+}
+
+// humanizeColumns returns a Transform which formats the numbers of the
+// named columns.
+func humanizeColumns(columns []string, format func(float64) string) Transform {
+	return func(c Content) (Content, error) {
+		humanized := make([]bool, len(c.header))
+		for _, name := range columns {
+			idx := c.columnIndex(name)
+			if idx < 0 {
+				return Content{}, fmt.Errorf("column %q does not exist", name)
+			}
+			humanized[idx] = true
+		}
+
+		rows := make([][]string, len(c.rows))
+		for i, row := range c.rows {
+			rows[i] = append([]string(nil), row...)
+			for j, value := range rows[i] {
+				if j >= len(humanized) || !humanized[j] {
+					continue
+				}
+
+				n, ok := parseNumber(value)
+				if !ok || math.IsInf(n, 0) || math.IsNaN(n) {
+					continue
+				}
+				rows[i][j] = format(n)
+			}
+		}
+
+		return Content{
+			header: c.header,
+			rows:   rows,
+		}, nil
+	}
+}
+
+// formatBytes formats a size in bytes with binary or decimal units.
+func formatBytes(n float64, si bool) string {
+	base, units := 1024.0, iecByteUnits
+	if si {
+		base, units = 1000, siByteUnits
+	}
+
+	abs, unit := math.Abs(n), 0
+	// sizes which would be rounded up to the base move to the next unit
+	for unit < len(units)-1 && math.Round(abs) >= base {
+		abs /= base
+		unit++
+	}
+	if unit == 0 {
+		return strconv.FormatFloat(n, 'f', -1, 64) + " " + units[0]
+	}
+
+	sign := ""
+	if n < 0 {
+		sign = "-"
+	}
+	if math.Round(abs*10) < 100 {
+		return sign + strconv.FormatFloat(abs, 'f', 1, 64) + " " + units[unit]
+	}
+
+	return sign + strconv.FormatFloat(abs, 'f', 0, 64) + " " + units[unit]
+}
+
+// formatDuration formats a duration given in seconds.
+func formatDuration(seconds float64) string {
+	sign := ""
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+
+	if math.Round(seconds*100)/100 < 60 {
+		return sign + formatShortDuration(seconds)
+	}
+
+	// the largest unit, which is found again after rounding, e.g. when
+	// 59m59.9s are rounded to 1h
+	largest := 0
+	for seconds < durationUnits[largest].seconds {
+		largest++
+	}
+	smallest := min(largest+durationParts-1, len(durationUnits)-1)
+	rest := math.Round(seconds/durationUnits[smallest].seconds) * durationUnits[smallest].seconds
+
+	var b strings.Builder
+	for _, unit := range durationUnits[:smallest+1] {
+		count := math.Floor(rest / unit.seconds)
+		rest -= count * unit.seconds
+		if count > 0 {
+			b.WriteString(strconv.FormatFloat(count, 'f', 0, 64) + unit.name)
+		}
+	}
+
+	return sign + b.String()
+}
+
+// formatShortDuration formats a duration of less than a minute in the
+// largest unit which fits, with up to two decimals.
+func formatShortDuration(seconds float64) string {
+	if seconds == 0 {
+		return "0s"
+	}
+
+	for _, unit := range shortDurationUnits {
+		v := math.Round(seconds*unit.scale*100) / 100
+		if v >= 1 || unit.name == "ns" {
+			return strconv.FormatFloat(v, 'f', -1, 64) + unit.name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,32 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanize(t *testing.T) {
+	c := parseCSV(t, "size,uptime\n1536,90061\n1000000,1.5\n")
+
+	tests := []struct {
+		name      string
+		transform Transform
+		want      string
+	}{
+		{"iec", HumanizeBytes([]string{"size"}, false), "size,uptime\n1.5 KiB,90061\n977 KiB,1.5\n"},
+		{"si", HumanizeBytes([]string{"size"}, true), "size,uptime\n1.5 kB,90061\n1.0 MB,1.5\n"},
+		{"durations", HumanizeDurations([]string{"uptime"}, time.Second), "size,uptime\n1536,1d1h1m\n1000000,1.5s\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.transform(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := toCSV(t, got); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JoinKind selects which unmatched rows are kept by Join.
+type JoinKind int
+
+const (
+	// InnerJoin keeps only rows with a match on both sides.
+	InnerJoin JoinKind = iota
+	// LeftJoin additionally keeps left rows without a match.
+	LeftJoin
+	// RightJoin additionally keeps right rows without a match.
+	RightJoin
+	// OuterJoin additionally keeps unmatched rows of both sides.
+	OuterJoin
+)
+
+// ParseJoinKind returns the JoinKind with the given name, one of inner,
+// left, right or outer.
+func ParseJoinKind(name string) (JoinKind, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "inner":
+		return InnerJoin, nil
+	case "left":
+		return LeftJoin, nil
+	case "right":
+		return RightJoin, nil
+	case "outer", "full":
+		return OuterJoin, nil
+	}
+
+	return 0, fmt.Errorf("%q is not a supported join kind", name)
+}
+
+// Join combines the rows of left and right whose values in the onLeft
+// and onRight columns are equal. The result contains all left columns
+// followed by all right columns except onRight. Rows are ordered like
+// the left rows, unmatched right rows are appended at the end.
+func Join(left, right Content, onLeft, onRight string, kind JoinKind) (Content, error) {
+	li := left.columnIndex(onLeft)
+	if li < 0 {
+		return Content{}, fmt.Errorf("column %q does not exist in the left input", onLeft)
+	}
+
+	ri := right.columnIndex(onRight)
+	if ri < 0 {
+		return Content{}, fmt.Errorf("column %q does not exist in the right input", onRight)
+	}
+
+	header := append([]string{}, left.header...)
+	for j, h := range right.header {
+		if j != ri {
+			header = append(header, h)
+		}
+	}
+
+	// rightRest returns the values of right row i without the key
+	// column.
+	rightRest := func(i int) []string {
+		var out []string
+		for j := range right.header {
+			if j != ri {
+				out = append(out, right.At(i, j))
+			}
+		}
+		return out
+	}
+
+	index := map[string][]int{}
+	for i := range right.rows {
+		key := right.At(i, ri)
+		index[key] = append(index[key], i)
+	}
+
+	matched := make([]bool, len(right.rows))
+	var rows [][]string
+	for i := range left.rows {
+		leftRow := make([]string, len(left.header))
+		for j := range left.header {
+			leftRow[j] = left.At(i, j)
+		}
+
+		matches := index[leftRow[li]]
+		for _, m := range matches {
+			matched[m] = true
+			rows = append(rows, append(append([]string{}, leftRow...), rightRest(m)...))
+		}
+
+		if len(matches) == 0 && (kind == LeftJoin || kind == OuterJoin) {
+			rows = append(rows, append(leftRow, make([]string, len(right.header)-1)...))
+		}
+	}
+
+	if kind == RightJoin || kind == OuterJoin {
+		for i := range right.rows {
+			if matched[i] {
+				continue
+			}
+
+			leftRow := make([]string, len(left.header))
+			leftRow[li] = right.At(i, ri)
+			rows = append(rows, append(leftRow, rightRest(i)...))
+		}
+	}
+
+	return Content{
+		header: header,
+		rows:   rows,
+	}, nil
+}
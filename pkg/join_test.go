@@ -0,0 +1,32 @@
+package pkg
+
+import "testing"
+
+func TestJoin(t *testing.T) {
+	left := "id,name\n1,apple\n2,pear\n3,kiwi\n"
+	right := "fruit,qty\n1,5\n3,2\n4,9\n"
+
+	tests := []struct {
+		kind JoinKind
+		want string
+	}{
+		{InnerJoin, "id,name,qty\n1,apple,5\n3,kiwi,2\n"},
+		{LeftJoin, "id,name,qty\n1,apple,5\n2,pear,\n3,kiwi,2\n"},
+		{RightJoin, "id,name,qty\n1,apple,5\n3,kiwi,2\n4,,9\n"},
+		{OuterJoin, "id,name,qty\n1,apple,5\n2,pear,\n3,kiwi,2\n4,,9\n"},
+	}
+
+	for _, tt := range tests {
+		c, err := Join(parseCSV(t, left), parseCSV(t, right), "id", "fruit", tt.kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := toCSV(t, c); got != tt.want {
+			t.Errorf("join %d: got\n%s\nwant\n%s", tt.kind, got, tt.want)
+		}
+	}
+
+	if _, err := Join(parseCSV(t, left), parseCSV(t, right), "id", "missing", InnerJoin); err == nil {
+		t.Error("expected an error for a missing column")
+	}
+}
@@ -0,0 +1,164 @@
+package pkg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// jsonNumber matches the numbers valid in JSON, so that e.g. "007" or
+// "1e" are kept as strings.
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d+)?$`)
+
+// JSONRenderer is a renderer implementation that emits a JSON array with
+// an object per row. The keys of the objects keep the column order.
+type JSONRenderer struct {
+	// InferTypes emits the values of numeric and boolean columns, see
+	// Content.Types, as JSON numbers and booleans instead of strings.
+	// Null values and the empty values of these columns are emitted as
+	// null.
+	InferTypes bool
+	// Nulls are the texts of values which are not set, see
+	// TableRenderer.Nulls.
+	Nulls *NullStrings
+	// Compact writes the array on a single line instead of indenting it.
+	Compact bool
+}
+
+// Render writes the Content as JSON array to the writer.
+func (j *JSONRenderer) Render(c Content, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	types := jsonTypes(c, j.InferTypes, j.Nulls)
+
+	if len(c.rows) == 0 {
+		bw.WriteString("[]\n")
+		return bw.Flush()
+	}
+
+	bw.WriteString("[")
+	for i, row := range c.rows {
+		if err := c.canceled(i); err != nil {
+			return err
+		}
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		if !j.Compact {
+			bw.WriteString("\n  ")
+		}
+		bw.Write(jsonObject(c.header, row, types, j.Nulls, !j.Compact))
+	}
+	if !j.Compact {
+		bw.WriteString("\n")
+	}
+	bw.WriteString("]\n")
+
+	return bw.Flush()
+}
+
+// jsonTypes returns the column types if they are to be inferred, and
+// nil otherwise.
+func jsonTypes(c Content, inferTypes bool, nulls *NullStrings) []ColumnType {
+	if !inferTypes {
+		return nil
+	}
+
+	return c.typesWith(nulls)
+}
+
+// jsonObject encodes a row as JSON object, indented to be nested in an
+// array if indent is set. The values are encoded according to the
+// column types, or as strings if types is nil.
+func jsonObject(header, row []string, types []ColumnType, nulls *NullStrings, indent bool) []byte {
+	var b bytes.Buffer
+
+	b.WriteString("{")
+	for i, name := range header {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		if indent {
+			b.WriteString("\n    ")
+		}
+		b.Write(jsonString(name))
+		b.WriteString(":")
+		if indent {
+			b.WriteString(" ")
+		}
+
+		value := ""
+		if i < len(row) {
+			value = row[i]
+		}
+		switch {
+		case types != nil && isNull(value, nulls):
+			b.WriteString("null")
+		case i < len(types):
+			b.Write(jsonValue(value, types[i]))
+		default:
+			b.Write(jsonString(value))
+		}
+	}
+	if indent && len(header) > 0 {
+		b.WriteString("\n  ")
+	}
+	b.WriteString("}")
+
+	return b.Bytes()
+}
+
+func jsonValue(value string, t ColumnType) []byte {
+	switch {
+	case t == TypeString || t == TypeTime:
+		return jsonString(value)
+	case value == "":
+		return []byte("null")
+	case t == TypeBool:
+		return []byte(strings.ToLower(value))
+	case jsonNumber.MatchString(value):
+		return []byte(value)
+	}
+
+	// numbers JSON does not support, e.g. "+1" or "NaN"
+	return jsonString(value)
+}
+
+// jsonString encodes the string without escaping HTML characters, which
+// encoding/json does by default.
+func jsonString(s string) []byte {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n"))
+}
+
+// NDJSONRenderer is a renderer implementation that emits newline
+// delimited JSON, also known as JSON Lines: an object per row and line,
+// e.g. to pipe the rows into jq or bulk loaders.
+type NDJSONRenderer struct {
+	// InferTypes emits the values of numeric and boolean columns, see
+	// Content.Types, as JSON numbers and booleans instead of strings.
+	// Null values and the empty values of these columns are emitted as
+	// null.
+	InferTypes bool
+	// Nulls are the texts of values which are not set, see
+	// TableRenderer.Nulls.
+	Nulls *NullStrings
+}
+
+// Render writes the Content as JSON lines to the writer.
+func (n *NDJSONRenderer) Render(c Content, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	types := jsonTypes(c, n.InferTypes, n.Nulls)
+	for _, row := range c.rows {
+		bw.Write(jsonObject(c.header, row, types, n.Nulls, false))
+		bw.WriteString("\n")
+	}
+
+	return bw.Flush()
+}
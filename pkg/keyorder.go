@@ -0,0 +1,132 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ColumnOrder selects the order of the columns of JSON documents, whose
+// objects may hold different keys.
+type ColumnOrder int
+
+const (
+	// OrderDocument orders the columns like their keys first appear in
+	// the document.
+	OrderDocument ColumnOrder = iota
+	// OrderAlphabetical orders the columns alphabetically.
+	OrderAlphabetical
+)
+
+// ParseColumnOrder returns the order with the given name, i.e.
+// "document" or "alphabetical".
+func ParseColumnOrder(name string) (ColumnOrder, error) {
+	switch strings.ToLower(name) {
+	case "document", "":
+		return OrderDocument, nil
+	case "alphabetical", "alpha":
+		return OrderAlphabetical, nil
+	}
+
+	return OrderDocument, fmt.Errorf("unknown column order %q, supported orders: document, alphabetical", name)
+}
+
+// jsonKeyPaths returns the keys of the objects of the JSON array in the
+// order they first appear. With nested set, the keys of nested objects
+// and the indices of nested arrays are included in dot-notation, e.g.
+// "user.name" or "tags.0", like flattenRows names them. The document
+// must be valid.
+func jsonKeyPaths(data []byte, nested bool) []string {
+	var paths []string
+	seen := map[string]struct{}{}
+	add := func(path string) {
+		if _, ok := seen[path]; !ok {
+			seen[path] = struct{}{}
+			paths = append(paths, path)
+		}
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	if _, err := d.Token(); err != nil {
+		return nil
+	}
+	for d.More() {
+		if err := walkJSONKeys(d, "", nested, add); err != nil {
+			break
+		}
+	}
+
+	return paths
+}
+
+// walkJSONKeys reads the next value from the decoder and passes the
+// paths of its keys to add.
+func walkJSONKeys(d *json.Decoder, path string, nested bool, add func(string)) error {
+	tok, err := d.Token()
+	if err != nil {
+		return err
+	}
+
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		for d.More() {
+			key, err := d.Token()
+			if err != nil {
+				return err
+			}
+
+			p := join(fmt.Sprint(key))
+			if path == "" || nested {
+				add(p)
+			}
+			if err := walkJSONKeys(d, p, nested, add); err != nil {
+				return err
+			}
+		}
+		_, err = d.Token()
+	case json.Delim('['):
+		for i := 0; d.More(); i++ {
+			p := join(strconv.Itoa(i))
+			if nested {
+				add(p)
+			}
+			if err := walkJSONKeys(d, p, nested, add); err != nil {
+				return err
+			}
+		}
+		_, err = d.Token()
+	}
+
+	return err
+}
+
+// sortByRank sorts the keys by their index in ranked. Keys missing in
+// ranked follow in alphabetical order.
+func sortByRank(keys, ranked []string) {
+	rank := make(map[string]int, len(ranked))
+	for i, key := range ranked {
+		rank[key] = i
+	}
+
+	sort.Slice(keys, func(a, b int) bool {
+		ra, oka := rank[keys[a]]
+		rb, okb := rank[keys[b]]
+		switch {
+		case oka && okb:
+			return ra < rb
+		case oka != okb:
+			return oka
+		}
+		return keys[a] < keys[b]
+	})
+}
@@ -0,0 +1,87 @@
+package pkg
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`$`, `\$`,
+	`#`, `\#`,
+	`_`, `\_`,
+	`{`, `\{`,
+	`}`, `\}`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+	"\r\n", " ",
+	"\n", " ",
+)
+
+// LaTeXRenderer is a renderer implementation that emits a LaTeX tabular
+// environment. Numeric columns are right-aligned, special characters are
+// escaped.
+type LaTeXRenderer struct {
+	// Booktabs uses the rules of the booktabs package, which must be
+	// loaded by the document, instead of \hline.
+	Booktabs bool
+	// Title and Caption are set as \caption above respectively below
+	// the tabular, which is then wrapped in a table float.
+	Title, Caption string
+}
+
+// Render writes the Content as LaTeX table to the writer.
+func (l *LaTeXRenderer) Render(c Content, w io.Writer) error {
+	spec := make([]byte, len(c.header))
+	for i, numeric := range numericColumns(c) {
+		spec[i] = 'l'
+		if numeric {
+			spec[i] = 'r'
+		}
+	}
+
+	top, mid, bottom := `\hline`, `\hline`, `\hline`
+	if l.Booktabs {
+		top, mid, bottom = `\toprule`, `\midrule`, `\bottomrule`
+	}
+
+	bw := bufio.NewWriter(w)
+
+	float := l.Title != "" || l.Caption != ""
+	if float {
+		bw.WriteString(`\begin{table}` + "\n")
+		bw.WriteString(`\centering` + "\n")
+	}
+	if l.Title != "" {
+		bw.WriteString(`\caption{` + latexEscaper.Replace(l.Title) + "}\n")
+	}
+	bw.WriteString(`\begin{tabular}{` + string(spec) + "}\n")
+	bw.WriteString(top + "\n")
+	bw.WriteString(latexRow(c.header) + "\n")
+	bw.WriteString(mid + "\n")
+	for _, row := range c.rows {
+		bw.WriteString(latexRow(row) + "\n")
+	}
+	bw.WriteString(bottom + "\n")
+	bw.WriteString(`\end{tabular}` + "\n")
+	if l.Caption != "" {
+		bw.WriteString(`\caption{` + latexEscaper.Replace(l.Caption) + "}\n")
+	}
+	if float {
+		bw.WriteString(`\end{table}` + "\n")
+	}
+
+	return bw.Flush()
+}
+
+func latexRow(row []string) string {
+	values := make([]string, len(row))
+	for i, value := range row {
+		values[i] = latexEscaper.Replace(value)
+	}
+
+	return strings.Join(values, " & ") + ` \\`
+}
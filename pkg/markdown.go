@@ -0,0 +1,191 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// MarkdownRenderer is a renderer implementation that emits GitHub
+// flavored markdown pipe tables. Numeric columns are right aligned.
+type MarkdownRenderer struct {
+	// Title is written as bold paragraph above the table, Caption as
+	// "Table: ..." paragraph below it, as understood by pandoc.
+	Title, Caption string
+}
+
+// Render writes the Content as a markdown table to the writer.
+func (m *MarkdownRenderer) Render(c Content, w io.Writer) error {
+	header := make([]string, len(c.header))
+	for i, value := range c.header {
+		header[i] = escapeMarkdown(value)
+	}
+
+	rows := make([][]string, len(c.rows))
+	for i, row := range c.rows {
+		rows[i] = make([]string, len(header))
+		for j := range header {
+			if j < len(row) {
+				rows[i][j] = escapeMarkdown(row[j])
+			}
+		}
+	}
+
+	widths := make([]int, len(header))
+	for i, value := range header {
+		widths[i] = tablewriter.DisplayWidth(value)
+	}
+	for _, row := range rows {
+		for i, value := range row {
+			if width := tablewriter.DisplayWidth(value); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	numeric := numericColumns(c)
+	delimiter := make([]string, len(header))
+	for i := range header {
+		// The delimiter row needs at least three dashes.
+		if widths[i] < 3 {
+			widths[i] = 3
+		}
+
+		if numeric[i] {
+			delimiter[i] = strings.Repeat("-", widths[i]-1) + ":"
+		} else {
+			delimiter[i] = strings.Repeat("-", widths[i])
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	if m.Title != "" {
+		bw.WriteString("**" + escapeMarkdown(m.Title) + "**\n\n")
+	}
+	writeMarkdownRow(bw, header, widths, nil)
+	writeMarkdownRow(bw, delimiter, widths, nil)
+	for _, row := range rows {
+		writeMarkdownRow(bw, row, widths, numeric)
+	}
+	if m.Caption != "" {
+		bw.WriteString("\nTable: " + escapeMarkdown(m.Caption) + "\n")
+	}
+
+	return bw.Flush()
+}
+
+func writeMarkdownRow(w *bufio.Writer, row []string, widths []int, rightAlign []bool) {
+	w.WriteString("|")
+	for i, value := range row {
+		w.WriteString(" ")
+		if rightAlign != nil && rightAlign[i] {
+			w.WriteString(tablewriter.PadLeft(value, " ", widths[i]))
+		} else {
+			w.WriteString(tablewriter.PadRight(value, " ", widths[i]))
+		}
+		w.WriteString(" |")
+	}
+	w.WriteString("\n")
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`|`, `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// MarkdownParser is a parser implementation that parses the first
+// GitHub flavored markdown pipe table of a document, so tables written
+// by MarkdownRenderer can be read back.
+type MarkdownParser struct{}
+
+var markdownDelimiterRow = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+
+// Parse converts the content of a reader to the Content representation.
+func (m *MarkdownParser) Parse(reader io.Reader) (Content, error) {
+	s := bufio.NewScanner(reader)
+	s.Buffer(nil, 1024*1024)
+
+	var c Content
+	var previous string
+	inTable := false
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+
+		if !inTable {
+			if previous != "" && strings.Contains(previous, "|") && markdownDelimiterRow.MatchString(line) {
+				c.header = splitMarkdownRow(previous)
+				inTable = true
+			}
+			previous = line
+			continue
+		}
+
+		if line == "" || !strings.Contains(line, "|") {
+			break
+		}
+
+		row := splitMarkdownRow(line)
+		// GFM pads short rows and ignores surplus cells.
+		if len(row) < len(c.header) {
+			row = append(row, make([]string, len(c.header)-len(row))...)
+		}
+		c.rows = append(c.rows, row[:len(c.header)])
+	}
+
+	if err := s.Err(); err != nil {
+		return Content{}, err
+	}
+
+	if !inTable {
+		return Content{}, fmt.Errorf("no markdown table found")
+	}
+
+	return c, nil
+}
+
+var markdownUnescaper = strings.NewReplacer(
+	`\|`, `|`,
+	"<br>", "\n",
+	"<br/>", "\n",
+	"<br />", "\n",
+)
+
+// splitMarkdownRow splits a table row into its cells, honouring escaped
+// pipes.
+func splitMarkdownRow(line string) []string {
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteString(`\|`)
+			i++
+		case line[i] == '|':
+			cells = append(cells, cell.String())
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	cells = append(cells, cell.String())
+
+	for i, v := range cells {
+		cells[i] = markdownUnescaper.Replace(strings.TrimSpace(v))
+	}
+
+	return cells
+}
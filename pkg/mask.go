@@ -0,0 +1,114 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	// maskText replaces masked values, regardless of their length, so
+	// that the length is not revealed either.
+	maskText = "****"
+	// maskVisible is the number of trailing characters MaskPartial
+	// keeps, e.g. the last digits of card numbers.
+	maskVisible = 4
+	// maskHashLen is the number of hex digits of hashed values.
+	maskHashLen = 12
+)
+
+// MaskMode selects how Mask hides the values of a column.
+type MaskMode int
+
+const (
+	// MaskFull replaces values with "****".
+	MaskFull MaskMode = iota
+	// MaskPartial keeps the last four characters, e.g. "****1234".
+	// Values of up to four characters are masked fully.
+	MaskPartial
+	// MaskHash replaces values with the first 12 hex digits of their
+	// SHA-256 hash, so that equal values can still be told apart from
+	// others, e.g. to count or join them. Values which are easy to
+	// guess, like phone numbers, can be recovered by hashing all of
+	// their possible values though.
+	MaskHash
+)
+
+var maskModeNames = []string{"full", "partial", "hash"}
+
+// ParseMaskMode returns the mode with the given name, i.e. "full",
+// "partial" or "hash".
+func ParseMaskMode(name string) (MaskMode, error) {
+	for i, modeName := range maskModeNames {
+		if strings.EqualFold(strings.TrimSpace(name), modeName) {
+			return MaskMode(i), nil
+		}
+	}
+
+	return MaskFull, fmt.Errorf("unknown mask mode %q, supported modes: %s", name, strings.Join(maskModeNames, ", "))
+}
+
+func (m MaskMode) String() string {
+	if m < 0 || int(m) >= len(maskModeNames) {
+		return fmt.Sprintf("MaskMode(%d)", int(m))
+	}
+
+	return maskModeNames[m]
+}
+
+// Mask returns a Transform which hides the values of the named columns
+// according to their mode, e.g. before sharing a table of production
+// data. Empty values are kept. It fails if a column does not exist.
+func Mask(modes map[string]MaskMode) Transform {
+	return func(c Content) (Content, error) {
+		masked := make([]*MaskMode, len(c.header))
+		for name, mode := range modes {
+			idx := c.columnIndex(name)
+			if idx < 0 {
+				return Content{}, fmt.Errorf("column %q does not exist", name)
+			}
+			mode := mode
+			masked[idx] = &mode
+		}
+
+		rows := make([][]string, len(c.rows))
+		for i, row := range c.rows {
+			if err := c.canceled(i); err != nil {
+				return Content{}, err
+			}
+			rows[i] = append([]string(nil), row...)
+			for j, value := range rows[i] {
+				if j < len(masked) && masked[j] != nil {
+					rows[i][j] = maskValue(value, *masked[j])
+				}
+			}
+		}
+
+		return Content{
+			header: c.header,
+			rows:   rows,
+		}, nil
+	}
+}
+
+// maskValue hides the value according to the mode.
+func maskValue(value string, mode MaskMode) string {
+	if value == "" {
+		return value
+	}
+
+	switch mode {
+	case MaskPartial:
+		runes := []rune(value)
+		if len(runes) <= maskVisible {
+			return maskText
+		}
+		return maskText + string(runes[len(runes)-maskVisible:])
+	case MaskHash:
+		sum := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(sum[:])[:maskHashLen]
+	}
+
+	return maskText
+}
@@ -0,0 +1,66 @@
+package pkg
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+var mediaWikiEscaper = strings.NewReplacer(
+	`|`, `&#124;`,
+	"\r\n", "<br />",
+	"\n", "<br />",
+)
+
+// MediaWikiRenderer is a renderer implementation that emits MediaWiki
+// {| ... |} tables.
+type MediaWikiRenderer struct {
+	// Class is the class attribute of the table, "wikitable" is used if
+	// it is unset.
+	Class string
+	// Title is set as caption of the table, Caption is written as
+	// paragraph below it.
+	Title, Caption string
+}
+
+// Render writes the Content as MediaWiki table to the writer.
+func (m *MediaWikiRenderer) Render(c Content, w io.Writer) error {
+	class := m.Class
+	if class == "" {
+		class = "wikitable"
+	}
+
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(`{| class="` + strings.ReplaceAll(class, `"`, "&quot;") + "\"\n")
+	if m.Title != "" {
+		bw.WriteString("|+ " + mediaWikiEscaper.Replace(m.Title) + "\n")
+	}
+	bw.WriteString("|-\n")
+	bw.WriteString("! " + mediaWikiRow(c.header, " !! ") + "\n")
+
+	for i := range c.rows {
+		values := make([]string, len(c.header))
+		for j := range values {
+			values[j] = c.At(i, j)
+		}
+
+		bw.WriteString("|-\n")
+		bw.WriteString("| " + mediaWikiRow(values, " || ") + "\n")
+	}
+	bw.WriteString("|}\n")
+	if m.Caption != "" {
+		bw.WriteString("\n" + m.Caption + "\n")
+	}
+
+	return bw.Flush()
+}
+
+func mediaWikiRow(values []string, separator string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = mediaWikiEscaper.Replace(value)
+	}
+
+	return strings.Join(escaped, separator)
+}
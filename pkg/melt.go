@@ -0,0 +1,53 @@
+package pkg
+
+import "fmt"
+
+// Melt turns a wide table into a long one, the inverse of Pivot. Every
+// row becomes a row per column other than the id columns, holding the
+// values of the id columns, the name of the column in the variable
+// column and its value in the value column. The variable and value
+// columns are named "variable" and "value" if the names are empty.
+func Melt(c Content, ids []string, variable, value string) (Content, error) {
+	if variable == "" {
+		variable = "variable"
+	}
+	if value == "" {
+		value = "value"
+	}
+
+	isID := make([]bool, len(c.header))
+	idIndices := make([]int, len(ids))
+	for i, name := range ids {
+		idIndices[i] = c.columnIndex(name)
+		if idIndices[i] < 0 {
+			return Content{}, fmt.Errorf("column %q does not exist", name)
+		}
+		isID[idIndices[i]] = true
+	}
+
+	header := make([]string, 0, len(ids)+2)
+	for _, idx := range idIndices {
+		header = append(header, c.header[idx])
+	}
+	header = append(header, variable, value)
+
+	var rows [][]string
+	for i := range c.rows {
+		for j, name := range c.header {
+			if isID[j] {
+				continue
+			}
+
+			row := make([]string, 0, len(header))
+			for _, idx := range idIndices {
+				row = append(row, c.At(i, idx))
+			}
+			rows = append(rows, append(row, name, c.At(i, j)))
+		}
+	}
+
+	return Content{
+		header: header,
+		rows:   rows,
+	}, nil
+}
@@ -0,0 +1,15 @@
+package pkg
+
+import "testing"
+
+func TestMelt(t *testing.T) {
+	c, err := Melt(parseCSV(t, "service,mon,tue\napi,6,3\nweb,1,\n"), []string{"service"}, "day", "errors")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "service,day,errors\napi,mon,6\napi,tue,3\nweb,mon,1\nweb,tue,\n"
+	if got := toCSV(t, c); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
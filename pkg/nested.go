@@ -0,0 +1,205 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NestedMode selects how values holding arrays or objects, e.g. of JSON
+// documents, are printed in a cell.
+type NestedMode int
+
+const (
+	// NestedJSON prints arrays and objects as compact JSON.
+	NestedJSON NestedMode = iota
+	// NestedList joins the elements of arrays with commas, e.g. "a, b",
+	// and the entries of objects likewise, e.g. "id: 1, name: a".
+	NestedList
+	// NestedCount prints the number of elements, e.g. "[3 items]" or
+	// "{2 keys}".
+	NestedCount
+	// NestedExpand prints every element of an array in a row of its own,
+	// repeating the other values of the row. The rows beyond the end of
+	// shorter arrays are empty. Objects are printed as compact JSON.
+	NestedExpand
+)
+
+var nestedModeNames = []string{"json", "list", "count", "expand"}
+
+// ParseNestedMode returns the mode with the given name, i.e. "json",
+// "list", "count" or "expand".
+func ParseNestedMode(name string) (NestedMode, error) {
+	for i, modeName := range nestedModeNames {
+		if strings.EqualFold(name, modeName) {
+			return NestedMode(i), nil
+		}
+	}
+
+	return NestedJSON, fmt.Errorf("unknown nested mode %q, supported modes: %s", name, strings.Join(nestedModeNames, ", "))
+}
+
+func (m NestedMode) String() string {
+	if m < 0 || int(m) >= len(nestedModeNames) {
+		return fmt.Sprintf("NestedMode(%d)", int(m))
+	}
+
+	return nestedModeNames[m]
+}
+
+// formatValue formats a decoded value for a cell. Scalars are formatted
+// with fmt.Sprint, arrays and objects according to the mode.
+func formatValue(v interface{}, mode NestedMode, nulls NullStrings) string {
+	if tables, ok := v.([]map[string]interface{}); ok {
+		// arrays of TOML tables
+		list := make([]interface{}, len(tables))
+		for i, table := range tables {
+			list[i] = table
+		}
+		v = list
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return nulls.Null
+	case []interface{}:
+		switch mode {
+		case NestedList:
+			values := make([]string, len(v))
+			for i, element := range v {
+				values[i] = formatValue(element, mode, nulls)
+			}
+			return strings.Join(values, ", ")
+		case NestedCount:
+			return countLabel(len(v), "[", "item", "]")
+		}
+		return compactJSON(v)
+	case map[string]interface{}, map[interface{}]interface{}:
+		m := stringKeys(v)
+		switch mode {
+		case NestedList:
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			entries := make([]string, len(keys))
+			for i, k := range keys {
+				entries[i] = k + ": " + formatValue(m[k], mode, nulls)
+			}
+			return strings.Join(entries, ", ")
+		case NestedCount:
+			return countLabel(len(m), "{", "key", "}")
+		}
+		return compactJSON(m)
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+// expandRow returns the cells of a row, which are several rows if the
+// mode is NestedExpand and the row holds arrays. The n-th row holds the
+// n-th element of every array, the other values are repeated.
+func expandRow(row map[string]interface{}, headers []string, mode NestedMode, nulls NullStrings) [][]string {
+	cells := make([]string, len(headers))
+	lists := map[int][]interface{}{}
+	n := 1
+	for j, header := range headers {
+		v, ok := row[header]
+		if !ok {
+			cells[j] = nulls.Missing
+			continue
+		}
+
+		if list, ok := v.([]interface{}); ok && mode == NestedExpand {
+			lists[j] = list
+			if len(list) > n {
+				n = len(list)
+			}
+			continue
+		}
+
+		cells[j] = formatValue(v, mode, nulls)
+	}
+
+	if len(lists) == 0 {
+		return [][]string{cells}
+	}
+
+	out := make([][]string, n)
+	for i := range out {
+		out[i] = append([]string(nil), cells...)
+		for j, list := range lists {
+			if i < len(list) {
+				out[i][j] = formatValue(list[i], mode, nulls)
+			}
+		}
+	}
+
+	return out
+}
+
+// countLabel returns e.g. "[3 items]".
+func countLabel(n int, open, noun, close string) string {
+	if n != 1 {
+		noun += "s"
+	}
+
+	return open + strconv.Itoa(n) + " " + noun + close
+}
+
+// stringKeys returns the object with its keys converted to strings, as
+// YAML mappings may have keys of other types.
+func stringKeys(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			m[fmt.Sprint(k)] = value
+		}
+		return m
+	}
+
+	return nil
+}
+
+// compactJSON encodes the value as JSON on a single line, falling back
+// to fmt.Sprint for values JSON cannot represent.
+func compactJSON(v interface{}) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(jsonCompatible(v)); err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// jsonCompatible converts nested YAML mappings with keys of other types
+// than strings, which encoding/json rejects.
+func jsonCompatible(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, element := range v {
+			out[i] = jsonCompatible(element)
+		}
+		return out
+	case map[string]interface{}, map[interface{}]interface{}:
+		m := stringKeys(v)
+		out := make(map[string]interface{}, len(m))
+		for k, value := range m {
+			out[k] = jsonCompatible(value)
+		}
+		return out
+	}
+
+	return v
+}
@@ -0,0 +1,114 @@
+package pkg
+
+// NullStrings are the texts of values which are not set in a document.
+// Empty strings are kept as they are.
+type NullStrings struct {
+	// Null is the text of null values, e.g. JSON null or YAML ~.
+	Null string
+	// Missing is the text of keys missing in some of the objects.
+	Missing string
+}
+
+// DefaultNullStrings are the texts used by parsers and renderers whose
+// NullStrings are unset.
+var DefaultNullStrings = NullStrings{Null: "<nil>", Missing: "<nil>"}
+
+// orDefault returns the NullStrings, or DefaultNullStrings if n is nil.
+func (n *NullStrings) orDefault() NullStrings {
+	if n == nil {
+		return DefaultNullStrings
+	}
+
+	return *n
+}
+
+// isNull reports whether the value is the text of a value which is not
+// set. If n is nil, the texts of DefaultNullStrings and the NULL of
+// query results are recognized.
+func isNull(value string, n *NullStrings) bool {
+	if n == nil {
+		return value == DefaultNullStrings.Null || value == DefaultNullStrings.Missing || value == sqlNull
+	}
+
+	return value == n.Null || value == n.Missing
+}
+
+// NullStringsParser is implemented by parsers of other packages which
+// print null values, so that WithNullString and WithMissingString apply
+// to them like to the JSONParser.
+type NullStringsParser interface {
+	Parser
+	// WithNullStrings returns a copy of the parser producing the given
+	// texts, unless its texts are set already.
+	WithNullStrings(n *NullStrings) Parser
+}
+
+// withNullStrings returns a copy of the parser producing the given
+// texts, unless its texts are set already. Other parsers are returned
+// as they are.
+func withNullStrings(p Parser, n *NullStrings) Parser {
+	switch p := p.(type) {
+	case *JSONParser:
+		if p.Nulls == nil {
+			c := *p
+			c.Nulls = n
+			return &c
+		}
+	case *YAMLParser:
+		if p.Nulls == nil {
+			c := *p
+			c.Nulls = n
+			return &c
+		}
+	case *TOMLParser:
+		if p.Nulls == nil {
+			c := *p
+			c.Nulls = n
+			return &c
+		}
+	case *XMLParser:
+		if p.Nulls == nil {
+			c := *p
+			c.Nulls = n
+			return &c
+		}
+	case NullStringsParser:
+		return p.WithNullStrings(n)
+	}
+
+	return p
+}
+
+// rendererWithNullStrings returns a copy of the renderer recognizing
+// the given texts, unless its texts are set already. Other renderers
+// are returned as they are.
+func rendererWithNullStrings(rd Renderer, n *NullStrings) Renderer {
+	switch r := rd.(type) {
+	case *TableRenderer:
+		if r.Nulls == nil {
+			c := *r
+			c.Nulls = n
+			return &c
+		}
+	case *JSONRenderer:
+		if r.Nulls == nil {
+			c := *r
+			c.Nulls = n
+			return &c
+		}
+	case *NDJSONRenderer:
+		if r.Nulls == nil {
+			c := *r
+			c.Nulls = n
+			return &c
+		}
+	case *SQLRenderer:
+		if r.Nulls == nil {
+			c := *r
+			c.Nulls = n
+			return &c
+		}
+	}
+
+	return rd
+}
@@ -0,0 +1,166 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// NumberFormat formats the numbers of a column for display, e.g. with
+// two decimals and thousands separators. Values which are not numbers
+// are printed as they are.
+type NumberFormat struct {
+	// Prefix and Suffix are written before and after the number, e.g.
+	// the currency "$" or the unit " kg". The sign of negative numbers
+	// precedes the prefix.
+	Prefix, Suffix string
+	// Precision is the number of decimals numbers are rounded to. The
+	// decimals are kept as they are if it is negative.
+	Precision int
+	// Group separates the thousands with the separator of the locale.
+	Group bool
+}
+
+// numberVerb matches the verb of a NumberFormat, e.g. "%'.2f".
+var numberVerb = regexp.MustCompile(`%('?)(?:\.(\d+))?([fd])`)
+
+// plainNumber matches numbers whose decimals can be kept as written.
+var plainNumber = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// ParseNumberFormat parses a format in the style of printf, e.g.
+// "%.2f", "$%'.2f", "%'d €" or "%.1f%%". The verb is %f, optionally
+// with a precision, or %d, which rounds to integers. The flag ' groups
+// the thousands, the text around the verb is the prefix and suffix, in
+// which %% is a percent sign.
+func ParseNumberFormat(s string) (NumberFormat, error) {
+	escaped := strings.ReplaceAll(s, "%%", "\x00")
+	loc := numberVerb.FindStringSubmatchIndex(escaped)
+	if loc == nil {
+		return NumberFormat{}, fmt.Errorf("%q is not a valid number format, expected e.g. %%.2f or %%'d", s)
+	}
+
+	prefix, suffix := escaped[:loc[0]], escaped[loc[1]:]
+	if strings.Contains(prefix, "%") || strings.Contains(suffix, "%") {
+		return NumberFormat{}, fmt.Errorf("%q is not a valid number format, it holds more than one verb", s)
+	}
+
+	f := NumberFormat{
+		Prefix:    strings.ReplaceAll(prefix, "\x00", "%"),
+		Suffix:    strings.ReplaceAll(suffix, "\x00", "%"),
+		Precision: -1,
+		Group:     loc[3] > loc[2],
+	}
+
+	switch {
+	case escaped[loc[6]:loc[7]] == "d":
+		f.Precision = 0
+	case loc[4] >= 0:
+		f.Precision, _ = strconv.Atoi(escaped[loc[4]:loc[5]])
+	}
+
+	return f, nil
+}
+
+// format formats the value if it is a number, using the decimal mark
+// and group separator given.
+func (f NumberFormat) format(value, decimal, group string) string {
+	n, ok := parseNumber(value)
+	if !ok || math.IsInf(n, 0) || math.IsNaN(n) {
+		return value
+	}
+
+	var digits string
+	switch trimmed := strings.TrimSpace(value); {
+	case f.Precision >= 0:
+		digits = strconv.FormatFloat(n, 'f', f.Precision, 64)
+	case plainNumber.MatchString(trimmed):
+		digits = trimmed
+	default:
+		digits = strconv.FormatFloat(n, 'f', -1, 64)
+	}
+
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+		if strings.Trim(digits, "0.") == "" {
+			// rounded to zero
+			sign = ""
+		}
+	}
+
+	integer, fraction, _ := strings.Cut(digits, ".")
+	if f.Group {
+		integer = groupDigits(integer, group)
+	}
+	if fraction != "" {
+		integer += decimal + fraction
+	}
+
+	return sign + f.Prefix + integer + f.Suffix
+}
+
+// groupDigits inserts the separator between groups of three digits.
+func groupDigits(digits, separator string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(separator)
+		}
+		b.WriteRune(d)
+	}
+
+	return b.String()
+}
+
+// numberSeparators are the decimal marks and group separators of
+// locales by language, or by language and region if they differ. Spaces
+// are no-break spaces, so that numbers are not wrapped.
+var numberSeparators = map[string][2]string{
+	"en": {".", ","}, "ja": {".", ","}, "zh": {".", ","}, "ko": {".", ","}, "he": {".", ","}, "th": {".", ","},
+	"de": {",", "."}, "es": {",", "."}, "it": {",", "."}, "nl": {",", "."}, "pt": {",", "."}, "id": {",", "."},
+	"tr": {",", "."}, "da": {",", "."}, "el": {",", "."}, "ro": {",", "."}, "hr": {",", "."}, "sl": {",", "."},
+	"fr": {",", "\u202f"}, "ru": {",", "\u00a0"}, "pl": {",", "\u00a0"}, "cs": {",", "\u00a0"}, "sk": {",", "\u00a0"},
+	"sv": {",", "\u00a0"}, "fi": {",", "\u00a0"}, "nb": {",", "\u00a0"}, "no": {",", "\u00a0"}, "uk": {",", "\u00a0"},
+	"hu": {",", "\u00a0"}, "bg": {",", "\u00a0"},
+	"de-ch": {".", "\u2019"}, "fr-ch": {".", "\u202f"}, "it-ch": {".", "\u2019"}, "es-mx": {".", ","},
+}
+
+// localeSeparators returns the decimal mark and the group separator of
+// the locale, e.g. "de", "de-CH" or "de_DE.UTF-8". Unknown locales use
+// those of English.
+func localeSeparators(locale string) (string, string) {
+	tag := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	tag, _, _ = strings.Cut(tag, ".")
+
+	if seps, ok := numberSeparators[tag]; ok {
+		return seps[0], seps[1]
+	}
+	language, _, _ := strings.Cut(tag, "-")
+	if seps, ok := numberSeparators[language]; ok {
+		return seps[0], seps[1]
+	}
+
+	return ".", ","
+}
+
+// columnNumberFormats returns the formats of the columns by index, nil
+// for columns without format.
+func columnNumberFormats(c Content, formats map[string]NumberFormat) ([]*NumberFormat, error) {
+	out := make([]*NumberFormat, len(c.header))
+	for name, f := range formats {
+		i := c.columnIndex(name)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		f := f
+		out[i] = &f
+	}
+
+	return out, nil
+}
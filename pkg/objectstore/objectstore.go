@@ -0,0 +1,134 @@
+// Package objectstore reads tables from S3 and Google Cloud Storage. It
+// is kept apart from package pkg, so that only programs reading objects
+// depend on the AWS and Google Cloud clients.
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/frjufvjn/table-pretty/pkg"
+)
+
+// Store reads tables from s3:// and gs:// URLs, e.g.
+// "s3://bucket/exports/users.csv". The objects are streamed, not
+// downloaded first. Credentials are taken from the environment, as the
+// AWS and Google Cloud command line tools do.
+type Store struct {
+	// Region is the AWS region of S3 buckets, the region of the AWS
+	// configuration is used if it is unset.
+	Region string
+	// Profile is the AWS shared configuration profile, the default
+	// profile is used if it is unset.
+	Profile string
+}
+
+// IsURL reports whether the name is an s3:// or gs:// URL.
+func IsURL(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasPrefix(lower, "s3://") || strings.HasPrefix(lower, "gs://")
+}
+
+// Open opens the object at the URL for reading, it must be closed by
+// the caller. It also returns the format of the extension of the object
+// key or of its content type, or an empty string if it is unknown.
+func (s *Store) Open(rawURL string) (io.ReadCloser, string, error) {
+	return s.OpenContext(context.Background(), rawURL)
+}
+
+// OpenContext is Open, which cancels the request, including reading
+// the object, once the context is done.
+func (s *Store) OpenContext(ctx context.Context, rawURL string) (io.ReadCloser, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	bucket, key := u.Host, strings.TrimPrefix(u.Path, "/")
+	if bucket == "" || key == "" {
+		return nil, "", fmt.Errorf("%q does not name an object, expected scheme://bucket/key", rawURL)
+	}
+
+	var body io.ReadCloser
+	var contentType string
+	switch strings.ToLower(u.Scheme) {
+	case "s3":
+		body, contentType, err = s.openS3(ctx, bucket, key)
+	case "gs":
+		body, contentType, err = openGCS(ctx, bucket, key)
+	default:
+		return nil, "", fmt.Errorf("%q is not a supported object storage scheme", u.Scheme)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	format, ok := pkg.FormatByExtension(pkg.TrimCompressionExt(key))
+	if !ok {
+		format, _ = pkg.FormatByMIMEType(contentType)
+	}
+
+	return body, format, nil
+}
+
+func (s *Store) openS3(ctx context.Context, bucket, key string) (io.ReadCloser, string, error) {
+	var opts []func(*config.LoadOptions) error
+	if s.Region != "" {
+		opts = append(opts, config.WithRegion(s.Region))
+	}
+	if s.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(s.Profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, "", fmt.Errorf("load aws config: %w", err)
+	}
+
+	out, err := s3.NewFromConfig(cfg).GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return out.Body, aws.ToString(out.ContentType), nil
+}
+
+func openGCS(ctx context.Context, bucket, key string) (io.ReadCloser, string, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("create gcs client: %w", err)
+	}
+
+	r, err := client.Bucket(bucket).Object(key).NewReader(ctx)
+	if err != nil {
+		client.Close()
+		return nil, "", err
+	}
+
+	return &gcsReader{Reader: r, client: client}, r.Attrs.ContentType, nil
+}
+
+// gcsReader closes the client along with the object reader.
+type gcsReader struct {
+	*storage.Reader
+	client *storage.Client
+}
+
+func (r *gcsReader) Close() error {
+	err := r.Reader.Close()
+	if cerr := r.client.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
@@ -0,0 +1,27 @@
+package objectstore
+
+import "testing"
+
+func TestIsURL(t *testing.T) {
+	tests := map[string]bool{
+		"s3://bucket/users.csv":   true,
+		"GS://bucket/users.csv":   true,
+		"https://example.com/a":   false,
+		"users.csv":               false,
+		"s3:/bucket/missing-host": false,
+	}
+
+	for name, want := range tests {
+		if got := IsURL(name); got != want {
+			t.Errorf("IsURL(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestOpenInvalidURL(t *testing.T) {
+	for _, name := range []string{"s3://bucket", "s3:///key", "ftp://bucket/key"} {
+		if _, _, err := (&Store{}).Open(name); err == nil {
+			t.Errorf("Open(%q): expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,499 @@
+package pkg
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Option configures the behaviour of Format.
+type Option func(*options)
+
+// Transform modifies the Content between parsing and rendering.
+type Transform func(Content) (Content, error)
+
+type options struct {
+	renderer   Renderer
+	clipboard  bool
+	clipFormat ClipboardFormat
+	banner     bool
+	plain      bool
+	maxWidth   int
+	style      Style
+	theme      *Theme
+	align      map[string]Alignment
+	colWidth   int
+	overflow   Overflow
+	width      int
+	footer     map[string]AggFunc
+	pageSize   int
+	title      string
+	caption    string
+	null       *string
+	missing    *string
+	nulls      *NullStrings
+	charset    string
+	sanitize   Sanitize
+	numbers    map[string]NumberFormat
+	locale     string
+	highlights []Highlight
+	bars       map[string]BarStyle
+	barWidth   int
+	batchSize  int
+	transforms []Transform
+	// masks and the transposition follow the other transformations,
+	// see allTransforms
+	masks     []Transform
+	transpose bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.plain {
+		o.banner = false
+	}
+
+	if o.null != nil || o.missing != nil {
+		nulls := DefaultNullStrings
+		if o.null != nil {
+			nulls.Null, nulls.Missing = *o.null, *o.null
+		}
+		if o.missing != nil {
+			nulls.Missing = *o.missing
+		}
+		o.nulls = &nulls
+	}
+
+	if o.renderer == nil {
+		o.renderer = &TableRenderer{
+			MaxWidth:       o.maxWidth,
+			MaxColumnWidth: o.colWidth,
+			Overflow:       o.overflow,
+			Width:          o.width,
+			Footer:         o.footer,
+			PageSize:       o.pageSize,
+			Style:          o.style,
+			Theme:          o.theme,
+			Align:          o.align,
+			Nulls:          o.nulls,
+			Sanitize:       o.sanitize,
+			NumberFormats:  o.numbers,
+			Locale:         o.locale,
+			Highlights:     o.highlights,
+			Bars:           o.bars,
+			BarWidth:       o.barWidth,
+		}
+	}
+
+	return o
+}
+
+// WithRenderer sets the renderer used to write the Content, a
+// TableRenderer is used by default.
+func WithRenderer(rd Renderer) Option {
+	return func(o *options) {
+		o.renderer = rd
+	}
+}
+
+// WithClipboard additionally copies the Content to the clipboard in
+// TSV format, so it can be pasted into a spreadsheet.
+func WithClipboard() Option {
+	return WithClipboardFormat(ClipboardTSV)
+}
+
+// WithClipboardFormat additionally copies the Content to the clipboard
+// in the format, e.g. WithClipboardFormat(ClipboardMarkdown).
+func WithClipboardFormat(f ClipboardFormat) Option {
+	return func(o *options) {
+		o.clipboard = true
+		o.clipFormat = f
+	}
+}
+
+// WithBanner writes decorative banners including the row count and
+// the clipboard status to the writer. The row count is replaced by the
+// title if WithTitle is used. Format writes nothing but the rendered
+// Content by default.
+func WithBanner() Option {
+	return func(o *options) {
+		o.banner = true
+	}
+}
+
+// WithPlainOutput suppresses the banners of WithBanner, so that the
+// output holds nothing but the rendered Content and is safe to pipe
+// into other programs.
+func WithPlainOutput() Option {
+	return func(o *options) {
+		o.plain = true
+	}
+}
+
+// WithMaxWidth sets the width at which the default TableRenderer wraps
+// cell values. It has no effect if WithRenderer is used.
+func WithMaxWidth(n int) Option {
+	return func(o *options) {
+		o.maxWidth = n
+	}
+}
+
+// WithMaxColumnWidth limits the display width of cell values of the
+// default TableRenderer, longer values are truncated with an ellipsis
+// unless WithOverflow selects otherwise. It has no effect if
+// WithRenderer is used.
+func WithMaxColumnWidth(n int) Option {
+	return func(o *options) {
+		o.colWidth = n
+	}
+}
+
+// WithOverflow selects how the default TableRenderer shows values wider
+// than WithMaxColumnWidth, e.g. WithOverflow(OverflowFootnote). It has
+// no effect if WithRenderer is used.
+func WithOverflow(overflow Overflow) Option {
+	return func(o *options) {
+		o.overflow = overflow
+	}
+}
+
+// WithWidth sets the maximum width of the default TableRenderer, e.g.
+// WithWidth(TerminalWidth(os.Stdout)) to fit the table into the
+// terminal. It has no effect if WithRenderer is used.
+func WithWidth(n int) Option {
+	return func(o *options) {
+		o.width = n
+	}
+}
+
+// WithFooter adds a footer row to the default TableRenderer holding
+// the aggregates of the columns, e.g. map[string]AggFunc{"price": Sum}
+// for totals. It has no effect if WithRenderer is used.
+func WithFooter(aggs map[string]AggFunc) Option {
+	return func(o *options) {
+		o.footer = aggs
+	}
+}
+
+// WithPageSize splits the default TableRenderer into tables of at most
+// n rows, each with its own header, so the header stays in sight when
+// paging through long tables. It has no effect if WithRenderer is used.
+func WithPageSize(n int) Option {
+	return func(o *options) {
+		o.pageSize = n
+	}
+}
+
+// WithTitle sets the title shown above the table, e.g. as plain text
+// line by the TableRenderer or as <caption> by the HTMLRenderer. It is
+// ignored by renderers which cannot show titles, such as CSVRenderer.
+func WithTitle(title string) Option {
+	return func(o *options) {
+		o.title = title
+	}
+}
+
+// WithCaption sets the caption shown below the table, e.g. as plain
+// text line by the TableRenderer or as "Table: ..." by the
+// MarkdownRenderer. It is ignored by renderers which cannot show
+// captions.
+func WithCaption(caption string) Option {
+	return func(o *options) {
+		o.caption = caption
+	}
+}
+
+// WithStyle sets the borders drawn by the default TableRenderer, e.g.
+// WithStyle(StyleRounded). It has no effect if WithRenderer is used.
+func WithStyle(s Style) Option {
+	return func(o *options) {
+		o.style = s
+	}
+}
+
+// WithBatchSize makes Format render the rows of parsers implementing
+// StreamParser as soon as n rows are read, like FormatStream, so that
+// the input is never held in memory as a whole. Every batch of n rows is
+// a separate table with its own header and borders, and the options
+// other than WithSanitize and the transformations are ignored. Other
+// parsers are not affected.
+func WithBatchSize(n int) Option {
+	return func(o *options) {
+		o.batchSize = n
+	}
+}
+
+// WithSanitize selects how the default TableRenderer prints control
+// characters and ANSI escape sequences in values, e.g.
+// WithSanitize(SanitizeEscape). It also applies to values copied to the
+// clipboard as TSV, which are quoted if they hold tabs or line breaks.
+func WithSanitize(mode Sanitize) Option {
+	return func(o *options) {
+		o.sanitize = mode
+	}
+}
+
+// WithNumberFormat formats the numbers of the named column in the
+// default TableRenderer, e.g. WithNumberFormat("price", f) with f
+// parsed from "$%'.2f" by ParseNumberFormat. The values are formatted
+// when they are rendered, so that transformations like sorting get the
+// numbers as they are. It has no effect if WithRenderer is used.
+func WithNumberFormat(column string, f NumberFormat) Option {
+	return func(o *options) {
+		if o.numbers == nil {
+			o.numbers = map[string]NumberFormat{}
+		}
+		o.numbers[column] = f
+	}
+}
+
+// WithLocale sets the decimal mark and thousands separator of
+// WithNumberFormat by locale, e.g. "de" or "fr-CH".
+func WithLocale(locale string) Option {
+	return func(o *options) {
+		o.locale = locale
+	}
+}
+
+// WithBar draws the numbers of the named column as bars in the default
+// TableRenderer, e.g. WithBar("latency", BarHorizontal). It has no
+// effect if WithRenderer is used.
+func WithBar(column string, style BarStyle) Option {
+	return func(o *options) {
+		if o.bars == nil {
+			o.bars = map[string]BarStyle{}
+		}
+		o.bars[column] = style
+	}
+}
+
+// WithBarWidth sets the width of the bars of WithBar.
+func WithBarWidth(n int) Option {
+	return func(o *options) {
+		o.barWidth = n
+	}
+}
+
+// WithHighlight colors the cells of rows matching the highlight in the
+// default TableRenderer, e.g. WithHighlight(h) with h parsed from
+// `status == "FAIL":red` by ParseHighlight. Highlights are applied in
+// order, later ones taking precedence. It has no effect if WithRenderer
+// is used.
+func WithHighlight(h Highlight) Option {
+	return func(o *options) {
+		o.highlights = append(o.highlights, h)
+	}
+}
+
+// WithTheme sets the colors of the default TableRenderer, which are
+// only applied when writing to a terminal and NO_COLOR is unset. It has
+// no effect if WithRenderer is used.
+func WithTheme(t *Theme) Option {
+	return func(o *options) {
+		o.theme = t
+	}
+}
+
+// WithAlignment sets the alignment of columns of the default
+// TableRenderer by name, e.g. map[string]Alignment{"name": AlignCenter}.
+// Numeric columns are right-aligned on the decimal point by default. It
+// has no effect if WithRenderer is used.
+func WithAlignment(align map[string]Alignment) Option {
+	return func(o *options) {
+		o.align = align
+	}
+}
+
+// WithEncoding converts the input of Format from the charset, e.g.
+// "shift_jis", to UTF-8, or detects the charset if it is "auto", see
+// DecodeReader. The input is read as UTF-8 by default.
+func WithEncoding(charset string) Option {
+	return func(o *options) {
+		o.charset = charset
+	}
+}
+
+// WithNullString sets the text of null values and missing keys, e.g.
+// "-", which parsers of JSON, YAML, TOML, XML and Parquet documents
+// print and renderers recognize, see NullStrings. Empty strings are
+// kept apart from null values.
+func WithNullString(s string) Option {
+	return func(o *options) {
+		o.null = &s
+	}
+}
+
+// WithMissingString sets the text of keys missing in some of the
+// objects, which is the one of WithNullString by default.
+func WithMissingString(s string) Option {
+	return func(o *options) {
+		o.missing = &s
+	}
+}
+
+// WithTransform adds a transformation which is applied to the Content
+// before it is rendered. Transformations are applied in the order the
+// options are passed, followed by those of WithMask and WithTranspose.
+func WithTransform(t Transform) Option {
+	return func(o *options) {
+		o.transforms = append(o.transforms, t)
+	}
+}
+
+// WithRowNumbers prepends a "#" column holding the row number, see
+// NumberRows.
+func WithRowNumbers() Option {
+	return WithTransform(func(c Content) (Content, error) {
+		return NumberRows(c), nil
+	})
+}
+
+// WithFilter keeps only the rows matching the expression, see Filter.
+func WithFilter(expression string) Option {
+	return WithTransform(Filter(expression))
+}
+
+// WithReplace replaces the matches of the regular expression in the
+// values of the named columns, or of all columns, see Replace.
+func WithReplace(columns []string, pattern, replacement string) Option {
+	return WithTransform(Replace(columns, pattern, replacement))
+}
+
+// WithMask hides the values of columns by name, e.g.
+// WithMask(map[string]MaskMode{"card": MaskPartial}), see Mask. Masks
+// are applied after all other transformations regardless of the order
+// of the options, so that e.g. WithFilter and WithSort see the actual
+// values, and before the Content is rendered and copied to the
+// clipboard, which holds the masked values as well.
+func WithMask(modes map[string]MaskMode) Option {
+	return func(o *options) {
+		o.masks = append(o.masks, Mask(modes))
+	}
+}
+
+// WithTimeFormat reformats the dates and times of the named columns,
+// or of all date and time columns, with the layout in the location,
+// e.g. WithTimeFormat(nil, "datetime", time.Local), see FormatTimes.
+func WithTimeFormat(columns []string, layout string, loc *time.Location) Option {
+	return WithTransform(FormatTimes(columns, layout, loc))
+}
+
+// WithHumanizeBytes prints the numbers of the named columns as sizes,
+// e.g. "1.5 KiB", or "1.5 kB" if si is set, see HumanizeBytes.
+func WithHumanizeBytes(columns []string, si bool) Option {
+	return WithTransform(HumanizeBytes(columns, si))
+}
+
+// WithHumanizeDurations prints the numbers of the named columns, which
+// count the unit, as durations, e.g. "1d2h3m", see HumanizeDurations.
+func WithHumanizeDurations(columns []string, unit time.Duration) Option {
+	return WithTransform(HumanizeDurations(columns, unit))
+}
+
+// WithComputedColumn sets the named column to the value of the
+// expression, e.g. WithComputedColumn("total", "price * qty"), see
+// ComputeColumn.
+func WithComputedColumn(name, expression string) Option {
+	return WithTransform(ComputeColumn(name, expression))
+}
+
+// WithSample keeps n rows chosen at random, see Sample.
+func WithSample(n int, rnd *rand.Rand) Option {
+	return WithTransform(Sample(n, rnd))
+}
+
+// WithDistinct drops duplicate rows, compared by the named columns or
+// by all columns, see Distinct.
+func WithDistinct(columns ...string) Option {
+	return WithTransform(func(c Content) (Content, error) {
+		return Distinct(c, columns...)
+	})
+}
+
+// WithDistinctValues replaces the rows with the distinct values of the
+// column and their counts, see DistinctValues.
+func WithDistinctValues(column string) Option {
+	return WithTransform(func(c Content) (Content, error) {
+		return DistinctValues(c, column)
+	})
+}
+
+// WithSort sorts the rows, see SortBy.
+func WithSort(spec string) Option {
+	return WithTransform(SortBy(spec))
+}
+
+// WithTopN keeps the first n rows of every group, sorted by the spec,
+// see TopN.
+func WithTopN(n int, groups []string, spec string) Option {
+	return WithTransform(TopN(n, groups, spec))
+}
+
+// WithTranspose swaps rows and columns, see Transpose. It is applied
+// last, after the masks of WithMask, which name the columns before
+// swapping.
+func WithTranspose() Option {
+	return func(o *options) {
+		o.transpose = true
+	}
+}
+
+// allTransforms returns the transformations in the order they are
+// applied: those of WithTransform and the like in the order of the
+// options, then the masks and the transposition.
+func (o *options) allTransforms() []Transform {
+	transforms := append(append([]Transform{}, o.transforms...), o.masks...)
+	if o.transpose {
+		transforms = append(transforms, func(c Content) (Content, error) {
+			return Transpose(c), nil
+		})
+	}
+
+	return transforms
+}
+
+// withTitle returns a copy of the renderer showing the title and
+// caption, which default to the ones of the renderer if they are empty.
+// It reports false if the renderer cannot show titles.
+func withTitle(rd Renderer, title, caption string) (Renderer, bool) {
+	set := func(t, c *string) {
+		if title != "" {
+			*t = title
+		}
+		if caption != "" {
+			*c = caption
+		}
+	}
+
+	switch r := rd.(type) {
+	case *TableRenderer:
+		c := *r
+		set(&c.Title, &c.Caption)
+		return &c, true
+	case *MarkdownRenderer:
+		c := *r
+		set(&c.Title, &c.Caption)
+		return &c, true
+	case *HTMLRenderer:
+		c := *r
+		set(&c.Title, &c.Caption)
+		return &c, true
+	case *LaTeXRenderer:
+		c := *r
+		set(&c.Title, &c.Caption)
+		return &c, true
+	case *OrgRenderer:
+		c := *r
+		set(&c.Title, &c.Caption)
+		return &c, true
+	case *MediaWikiRenderer:
+		c := *r
+		set(&c.Title, &c.Caption)
+		return &c, true
+	}
+
+	return rd, false
+}
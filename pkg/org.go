@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+var orgEscaper = strings.NewReplacer(
+	`|`, `\vert{}`,
+	"\r\n", " ",
+	"\n", " ",
+)
+
+// OrgRenderer is a renderer implementation that emits Emacs org-mode
+// tables. Numeric columns are right aligned, as org-mode does.
+type OrgRenderer struct {
+	// Title is set as #+CAPTION of the table, Caption is written as
+	// paragraph below it.
+	Title, Caption string
+}
+
+// Render writes the Content as an org-mode table to the writer.
+func (o *OrgRenderer) Render(c Content, w io.Writer) error {
+	header := make([]string, len(c.header))
+	for i, value := range c.header {
+		header[i] = orgEscaper.Replace(value)
+	}
+
+	rows := make([][]string, len(c.rows))
+	for i, row := range c.rows {
+		rows[i] = make([]string, len(header))
+		for j := range header {
+			if j < len(row) {
+				rows[i][j] = orgEscaper.Replace(row[j])
+			}
+		}
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, value := range row {
+			if width := tablewriter.DisplayWidth(value); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	separator := make([]string, len(widths))
+	for i, width := range widths {
+		separator[i] = strings.Repeat("-", width+2)
+	}
+
+	bw := bufio.NewWriter(w)
+	if o.Title != "" {
+		bw.WriteString("#+CAPTION: " + orgEscaper.Replace(o.Title) + "\n")
+	}
+	writeMarkdownRow(bw, header, widths, nil)
+	bw.WriteString("|" + strings.Join(separator, "+") + "|\n")
+	numeric := numericColumns(c)
+	for _, row := range rows {
+		writeMarkdownRow(bw, row, widths, numeric)
+	}
+	if o.Caption != "" {
+		bw.WriteString("\n" + o.Caption + "\n")
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,247 @@
+// Package parquet parses Apache Parquet files. It is kept apart from
+// package pkg, so that only programs reading Parquet files depend on
+// parquet-go. Importing it registers the "parquet" format, e.g.:
+//
+//	import _ "github.com/frjufvjn/table-pretty/pkg/parquet"
+package parquet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	parquetgo "github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/deprecated"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+func init() {
+	pkg.RegisterParser("parquet", func() pkg.Parser { return &Parser{} })
+	pkg.RegisterExtension(".parquet", "parquet")
+	pkg.RegisterMIMEType("application/vnd.apache.parquet", "parquet")
+}
+
+// Parser is a parser implementation that parses Apache Parquet files.
+// Every row is converted like a JSON object, so nested groups and lists
+// can be flattened.
+type Parser struct {
+	// Flatten turns nested groups and lists into separate columns using
+	// dot-notation, e.g. "address.city" or "tags.0".
+	Flatten bool
+	// MaxDepth limits how many levels are flattened, zero flattens all
+	// levels.
+	MaxDepth int
+	// Nested selects how lists and groups which are not flattened
+	// are printed, as compact JSON by default.
+	Nested pkg.NestedMode
+	// Nulls are the texts of null values and missing keys,
+	// pkg.DefaultNullStrings is used if it is unset.
+	Nulls *pkg.NullStrings
+}
+
+// Parse converts the content of a reader to the Content representation.
+// The whole file is read into memory, as the metadata of parquet files
+// is stored at their end.
+func (p *Parser) Parse(reader io.Reader) (pkg.Content, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return pkg.Content{}, err
+	}
+
+	file, err := parquetgo.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return pkg.Content{}, fmt.Errorf("failed to open parquet file: %w", err)
+	}
+
+	r := parquetgo.NewReader(file)
+	defer r.Close()
+
+	var rows []map[string]interface{}
+	for i := 1; ; i++ {
+		row := map[string]interface{}{}
+		err := r.Read(&row)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return pkg.Content{}, fmt.Errorf("row %d: %w", i, err)
+		}
+		rows = append(rows, parquetRow(file.Schema(), row))
+	}
+
+	if len(rows) == 0 {
+		var header []string
+		for _, field := range file.Schema().Fields() {
+			header = append(header, field.Name())
+		}
+		return pkg.NewContent(header, nil), nil
+	}
+
+	converter := pkg.DocumentConverter{Flatten: p.Flatten, MaxDepth: p.MaxDepth, Nested: p.Nested, Nulls: p.Nulls}
+	return converter.Content(rows), nil
+}
+
+// WithNullStrings returns a copy of the parser producing the given
+// texts, unless its texts are set already, see pkg.NullStringsParser.
+func (p *Parser) WithNullStrings(n *pkg.NullStrings) pkg.Parser {
+	if p.Nulls != nil {
+		return p
+	}
+
+	c := *p
+	c.Nulls = n
+	return &c
+}
+
+// parquetRow converts the values of a row according to the logical
+// types of the schema, e.g. timestamps stored as integers to times.
+func parquetRow(node parquetgo.Node, row map[string]interface{}) map[string]interface{} {
+	for _, field := range node.Fields() {
+		if v, ok := row[field.Name()]; ok {
+			row[field.Name()] = parquetValue(field, v)
+		}
+	}
+
+	return row
+}
+
+func parquetValue(node parquetgo.Node, v interface{}) interface{} {
+	if list, ok := v.([]interface{}); ok && node.Repeated() {
+		for i := range list {
+			list[i] = parquetElement(node, list[i])
+		}
+		return list
+	}
+
+	return parquetElement(node, v)
+}
+
+func parquetElement(node parquetgo.Node, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	if !node.Leaf() {
+		if isParquetList(node) {
+			return parquetList(node, v)
+		}
+		if m, ok := v.(map[string]interface{}); ok {
+			return parquetRow(node, m)
+		}
+		return v
+	}
+
+	lt := node.Type().LogicalType()
+	switch {
+	case lt != nil && lt.Timestamp != nil:
+		if n, ok := v.(int64); ok {
+			return parquetTimestamp(n, lt.Timestamp.Unit).Format(time.RFC3339Nano)
+		}
+	case lt != nil && lt.Date != nil:
+		if n, ok := v.(int32); ok {
+			return time.Unix(int64(n)*24*60*60, 0).UTC().Format("2006-01-02")
+		}
+	case lt != nil && lt.Decimal != nil:
+		return parquetDecimal(v, int(lt.Decimal.Scale))
+	}
+
+	switch v := v.(type) {
+	case []byte:
+		return string(v)
+	case deprecated.Int96:
+		// legacy timestamps as written by Spark and Impala
+		nanos := int64(v[0]) | int64(v[1])<<32
+		days := int64(v[2]) - julianUnixEpoch
+		return time.Unix(days*24*60*60, nanos).UTC().Format(time.RFC3339Nano)
+	}
+
+	return v
+}
+
+// julianUnixEpoch is the julian day number of 1970-01-01.
+const julianUnixEpoch = 2440588
+
+// isParquetList reports whether the node is a LIST group. The structure
+// is checked in addition to the logical type, as the logical type of
+// groups read from files is not exposed.
+func isParquetList(node parquetgo.Node) bool {
+	if lt := node.Type().LogicalType(); lt != nil && lt.List != nil {
+		return true
+	}
+
+	fields := node.Fields()
+	return len(fields) == 1 && fields[0].Repeated()
+}
+
+// parquetList unwraps the elements of a LIST group, which are nested in
+// a repeated group, e.g. {"list": [{"element": "a"}]}, or in the legacy
+// two-level layout stored in the repeated field itself.
+func parquetList(node parquetgo.Node, v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	fields := node.Fields()
+	if !ok || len(fields) != 1 {
+		return v
+	}
+
+	repeated := fields[0]
+	items, _ := m[repeated.Name()].([]interface{})
+	list := make([]interface{}, len(items))
+	for i, item := range items {
+		inner := repeated.Fields()
+		if element, ok := item.(map[string]interface{}); ok && !repeated.Leaf() && len(inner) == 1 {
+			list[i] = parquetValue(inner[0], element[inner[0].Name()])
+		} else {
+			list[i] = parquetElement(repeated, item)
+		}
+	}
+
+	return list
+}
+
+func parquetTimestamp(n int64, unit format.TimeUnit) time.Time {
+	switch {
+	case unit.Millis != nil:
+		return time.UnixMilli(n).UTC()
+	case unit.Micros != nil:
+		return time.UnixMicro(n).UTC()
+	}
+
+	return time.Unix(0, n).UTC()
+}
+
+// parquetDecimal formats a decimal stored as integer or as big-endian
+// two's complement bytes.
+func parquetDecimal(v interface{}, scale int) interface{} {
+	unscaled := new(big.Int)
+	switch v := v.(type) {
+	case int32:
+		unscaled.SetInt64(int64(v))
+	case int64:
+		unscaled.SetInt64(v)
+	case []byte:
+		unscaled.SetBytes(v)
+		if len(v) > 0 && v[0]&0x80 != 0 {
+			unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(v))*8))
+		}
+	default:
+		return v
+	}
+
+	digits := new(big.Int).Abs(unscaled).String()
+	if scale > 0 {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+	if unscaled.Sign() < 0 {
+		digits = "-" + digits
+	}
+
+	return digits
+}
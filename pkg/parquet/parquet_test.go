@@ -0,0 +1,102 @@
+package parquet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	parquetgo "github.com/parquet-go/parquet-go"
+)
+
+type fruit struct {
+	Name  string   `parquet:"name"`
+	Price float64  `parquet:"price"`
+	Note  *string  `parquet:"note,optional"`
+	Tags  []string `parquet:"tags,list"`
+}
+
+// writeFruits returns a parquet file of the fruits.
+func writeFruits(t *testing.T, fruits ...fruit) []byte {
+	t.Helper()
+
+	var b bytes.Buffer
+	w := parquetgo.NewGenericWriter[fruit](&b)
+	if _, err := w.Write(fruits); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.Bytes()
+}
+
+func TestFormat(t *testing.T) {
+	sweet := "sweet"
+	data := writeFruits(t,
+		fruit{Name: "apple", Price: 1.5, Note: &sweet, Tags: []string{"red", "round"}},
+		fruit{Name: "pear", Price: 2},
+	)
+
+	tests := []struct {
+		name   string
+		parser pkg.Parser
+		opts   []pkg.Option
+		want   string
+	}{
+		{
+			name:   "plain",
+			parser: &Parser{},
+			want:   "name,note,price,tags\napple,sweet,1.5,\"[\"\"red\"\",\"\"round\"\"]\"\npear,<nil>,2,[]\n",
+		},
+		{
+			name:   "flattened",
+			parser: &Parser{Flatten: true},
+			want:   "name,note,price,tags,tags.0,tags.1\napple,sweet,1.5,<nil>,red,round\npear,<nil>,2,[],<nil>,<nil>\n",
+		},
+		{
+			name:   "null string",
+			parser: &Parser{},
+			opts:   []pkg.Option{pkg.WithNullString("-"), pkg.WithTransform(pkg.SelectColumns([]string{"name", "note"}))},
+			want:   "name,note\napple,sweet\npear,-\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			opts := append([]pkg.Option{pkg.WithRenderer(&pkg.CSVRenderer{})}, tt.opts...)
+			if err := pkg.Format(tt.parser, bytes.NewReader(data), &b, opts...); err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	if format, ok := pkg.FormatByExtension("users.parquet"); !ok || format != "parquet" {
+		t.Errorf("the extension is registered as %q, %v", format, ok)
+	}
+
+	p, _, err := pkg.DetectParser(bytes.NewReader(writeFruits(t, fruit{Name: "apple"})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.(*Parser); !ok {
+		t.Errorf("detected %T, want *Parser", p)
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	c, err := (&Parser{}).Parse(bytes.NewReader(writeFruits(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := c.Header(); len(got) != 4 || len(c.Rows()) != 0 {
+		t.Errorf("got header %v and %d rows, want the 4 fields of the schema", got, len(c.Rows()))
+	}
+}
@@ -2,15 +2,13 @@ package pkg
 
 import (
 	"bytes"
-	"encoding/csv"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"sort"
-	"strconv"
-
-	"github.com/atotto/clipboard"
-	"github.com/olekukonko/tablewriter"
+	"strings"
 )
 
 // Parser describes an interface to Parse an arbitrary document into
@@ -19,114 +17,334 @@ type Parser interface {
 	Parse(io.Reader) (Content, error)
 }
 
-// Content is the intermediate representation before it is converted
-// to a table format.
-type Content struct {
-	header []string
-	rows   [][]string
+// ParserFunc is an adapter to allow the use of ordinary functions as
+// Parsers.
+type ParserFunc func(io.Reader) (Content, error)
+
+// Parse calls f(r).
+func (f ParserFunc) Parse(r io.Reader) (Content, error) {
+	return f(r)
 }
 
 // Format converts the content of the reader to a table format using
-// the supplied parser and writes it to the writer.
-func Format(p Parser, r io.Reader, w io.Writer, enablePbcopy bool) error {
-	c, err := p.Parse(r)
-	if err != nil {
+// the supplied parser and writes it to the writer. The behaviour can be
+// customised using options, e.g. WithRenderer or WithClipboard.
+func Format(p Parser, r io.Reader, w io.Writer, opts ...Option) error {
+	return FormatContext(context.Background(), p, r, w, opts...)
+}
+
+// parse parses the input with the parser. Stream parsers pass the rows
+// one by one and stop once the context is done, even if the input is
+// read already.
+func parse(ctx context.Context, p Parser, r io.Reader, o *options) (Content, error) {
+	sp, ok := p.(StreamParser)
+	switch {
+	case ok && ctx.Done() != nil:
+		var c Content
+		err := sp.ParseStream(r, func(header []string) error {
+			c.header = header
+			return nil
+		}, func(row []string) error {
+			c.rows = append(c.rows, row)
+			return canceled(ctx, len(c.rows))
+		})
+		if err != nil {
+			return Content{}, err
+		}
+		return c, nil
+	}
+
+	return p.Parse(r)
+}
+
+// FormatContent writes already parsed Content to the writer, in the
+// same way as Format does.
+func FormatContent(c Content, w io.Writer, opts ...Option) error {
+	return FormatContentContext(context.Background(), c, w, opts...)
+}
+
+// formatContent transforms and renders the Content. Its context is
+// checked between the transformations and passed to them and to the
+// renderer with the Content, see Content.canceled.
+func formatContent(ctx context.Context, c Content, w io.Writer, o *options) error {
+	var err error
+	for _, t := range o.allTransforms() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		c.ctx = ctx
+		if c, err = t(c); err != nil {
+			return err
+		}
+	}
+
+	c.ctx = ctx
+	if err := formatTable(c, w, o); err != nil {
 		return err
 	}
 
-	formatTable(c, w)
+	if o.clipboard {
+		if err := copyToClipboard(c, o.clipFormat, o.sanitize); err != nil {
+			return err
+		}
 
-	if enablePbcopy {
-		tsvPbcopy(c)
+		if o.banner {
+			fmt.Fprintf(w, "\n📎 %s RESULT\n", strings.ToUpper(o.clipFormat.String()))
+			fmt.Fprintf(w, "%s format is saved into clipboard successfully.\n%s\n", o.clipFormat, o.clipFormat.pasteHint())
+		}
 	}
 
 	return nil
 }
 
-// tsv format to clipboard
-func tsvPbcopy(c Content) {
-	fmt.Println("\n📎 TSV RESULT")
-	var tsv bytes.Buffer
-	for _, head := range c.header {
-		tsv.WriteString(head + "\t")
-	}
-	tsv.WriteString("\n")
-	for _, row := range c.rows {
-		for _, value := range row {
-			tsv.WriteString(value + "\t")
-		}
-		tsv.WriteString("\n")
+// FormatPbcopy is Format with the signature of the first releases,
+// whose enablePbcopy argument is replaced by options. Like those, it
+// writes the row count banner above the table and copies the Content to
+// the clipboard as TSV if enablePbcopy is set.
+//
+// Deprecated: Replace Format(p, r, w, true) by Format(p, r, w,
+// WithBanner(), WithClipboard()), and Format(p, r, w, false) by
+// Format(p, r, w, WithBanner()), or drop WithBanner for the table only.
+func FormatPbcopy(p Parser, r io.Reader, w io.Writer, enablePbcopy bool) error {
+	opts := []Option{WithBanner()}
+	if enablePbcopy {
+		opts = append(opts, WithClipboard())
 	}
-	err := clipboard.WriteAll(tsv.String())
-	if err != nil {
-		panic(err)
+
+	return Format(p, r, w, opts...)
+}
+
+// FormatWith converts the content of the reader using the supplied
+// parser and writes it to the writer using the supplied renderer.
+//
+// Deprecated: Use Format with WithRenderer and WithClipboard instead.
+func FormatWith(p Parser, rd Renderer, r io.Reader, w io.Writer, enablePbcopy bool) error {
+	opts := []Option{WithRenderer(rd)}
+	if enablePbcopy {
+		opts = append(opts, WithClipboard())
 	}
-	fmt.Println("tsv format is saved into clipboard successfully.\nYou can now paste it into an excel sheet.")
+
+	return Format(p, r, w, opts...)
 }
 
-// CSVParser is a parser implementation that parses CSV documents.
-type CSVParser struct{}
+// ErrClipboard is returned by Format if the Content could not be copied
+// to the clipboard, which is common on headless machines. The Content
+// has been rendered to the writer nevertheless.
+var ErrClipboard = errors.New("failed to copy to clipboard")
 
-// Parse converts the content of a reader to the Content representation.
-func (c *CSVParser) Parse(reader io.Reader) (Content, error) {
-	r := csv.NewReader(reader)
+// tsvPbcopy copies the Content to the clipboard as tab-separated
+// values, which spreadsheets paste into their cells.
+func tsvPbcopy(c Content, mode Sanitize) error {
+	var tsv bytes.Buffer
+	writeTSVRecord(&tsv, c.header, mode)
+	for _, row := range c.rows {
+		writeTSVRecord(&tsv, row, mode)
+	}
 
-	header, err := r.Read()
-	if err != nil {
-		return Content{}, err
+	return writeClipboard(tsv.String())
+}
+
+// writeTSVRecord writes the values separated by tabs and terminated by
+// a line break.
+func writeTSVRecord(b *bytes.Buffer, values []string, mode Sanitize) {
+	for j, value := range values {
+		if j > 0 {
+			b.WriteByte('\t')
+		}
+		b.WriteString(tsvField(value, mode))
 	}
+	b.WriteByte('\n')
+}
 
-	rows, err := r.ReadAll()
-	if err != nil {
-		return Content{}, err
+// tsvField sanitizes the value for TSV. Values which still hold tabs or
+// line breaks, or hold quotes, are quoted with their quotes doubled,
+// like spreadsheets copy such cells. Spreadsheets paste them into a
+// single cell, and they are read back like CSV. Unless the mode is
+// SanitizeNone, values which spreadsheets would take as formulas are
+// prefixed with an apostrophe, so that they are pasted as text.
+func tsvField(value string, mode Sanitize) string {
+	value = sanitize(value, mode)
+	if mode != SanitizeNone && isFormula(value) {
+		value = "'" + value
+	}
+	if !strings.ContainsAny(value, "\t\r\n\"") {
+		return value
 	}
 
-	return Content{
-		header: header,
-		rows:   rows,
-	}, nil
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
+
+// isFormula reports whether the value starts like a formula of a
+// spreadsheet, i.e. with "=", "+", "-" or "@", and is not a number like
+// "-1.5".
+func isFormula(value string) bool {
+	if value == "" || !strings.ContainsRune("=+-@", rune(value[0])) {
+		return false
+	}
+	_, ok := parseNumber(value)
+
+	return !ok
 }
 
 // JSONParser is a parser implementation that parses JSON documents.
-type JSONParser struct{}
+// Numbers are kept exactly as written in the document.
+type JSONParser struct {
+	// Flatten turns nested objects and arrays into separate columns
+	// using dot-notation, e.g. "user.name" or "tags.0".
+	Flatten bool
+	// MaxDepth limits how many levels are flattened, zero flattens all
+	// levels.
+	MaxDepth int
+	// Nested selects how arrays and objects which are not flattened are
+	// printed, as compact JSON by default.
+	Nested NestedMode
+	// Nulls are the texts of null values and missing keys,
+	// DefaultNullStrings is used if it is unset.
+	Nulls *NullStrings
+	// Order selects the order of the columns, the order their keys
+	// first appear in the document by default.
+	Order ColumnOrder
+	// KeyOrder lists the keys which are the first columns, in that
+	// order, followed by the others according to Order.
+	KeyOrder []string
+}
 
 // Parse converts the content of a reader to the Content representation.
 func (j *JSONParser) Parse(reader io.Reader) (Content, error) {
-	r := json.NewDecoder(reader)
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return Content{}, err
+	}
+
+	// numbers are decoded as json.Number, which keeps their literal
+	// instead of rounding large integers to float64
+	r := json.NewDecoder(bytes.NewReader(data))
+	r.UseNumber()
 
 	var rows []map[string]interface{}
 	if err := r.Decode(&rows); err != nil {
-		return Content{}, err
+		return Content{}, jsonError(data, err)
+	}
+
+	if j.Flatten {
+		rows = flattenRows(rows, j.MaxDepth)
 	}
 
-	headers := collectHeader(rows)
-	sort.Strings(headers)
+	var seen []string
+	if j.Order == OrderDocument {
+		seen = jsonKeyPaths(data, j.Flatten)
+	}
 
-	var outputRows [][]string
-	for i, row := range rows {
-		outputRow := make([]string, len(headers))
-		for j, header := range headers {
-			if j == 0 {
-				outputRow[j] = strconv.Itoa(i + 1)
-			} else {
-				outputRow[j] = fmt.Sprintf("%v", row[header])
-			}
+	return fromMaps(rows, j.Nulls.orDefault(), j.Nested, j.KeyOrder, seen), nil
+}
+
+// jsonError converts errors of encoding/json into a ParseError.
+func jsonError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return offsetError(data, syntaxErr.Offset-1, err)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return offsetError(data, typeErr.Offset-1, err)
+	}
+
+	return err
+}
+
+// mapsToContent converts a list of decoded documents to the Content
+// representation, using the union of all keys as header.
+func mapsToContent(rows []map[string]interface{}, nulls *NullStrings, nested NestedMode) Content {
+	return fromMaps(rows, nulls.orDefault(), nested, nil, nil)
+}
+
+// DocumentConverter converts decoded documents to the Content
+// representation like the JSONParser converts JSON objects, so that
+// parsers of other packages, e.g. of Parquet files, flatten and print
+// nested values and nulls alike.
+type DocumentConverter struct {
+	// Flatten turns nested objects and arrays into separate columns
+	// using dot-notation, e.g. "user.name" or "tags.0".
+	Flatten bool
+	// MaxDepth limits how many levels are flattened, zero flattens all
+	// levels.
+	MaxDepth int
+	// Nested selects how arrays and objects which are not flattened
+	// are printed, as compact JSON by default.
+	Nested NestedMode
+	// Nulls are the texts of null values and missing keys,
+	// DefaultNullStrings is used if it is unset.
+	Nulls *NullStrings
+}
+
+// Content converts the documents, using the union of their keys in
+// alphabetical order as header.
+func (d DocumentConverter) Content(rows []map[string]interface{}) Content {
+	if d.Flatten {
+		rows = flattenRows(rows, d.MaxDepth)
+	}
+
+	return mapsToContent(rows, d.Nulls, d.Nested)
+}
+
+// FromMaps converts a slice of maps, e.g. decoded JSON objects, to the
+// Content representation. The keys given in keyOrder are the first
+// columns, in that order, followed by the remaining keys of all rows in
+// alphabetical order. Values are formatted with fmt.Sprint, arrays and
+// objects as compact JSON, nil values and missing keys are printed as
+// "<nil>", see DefaultNullStrings.
+func FromMaps(rows []map[string]interface{}, keyOrder ...string) Content {
+	return fromMaps(rows, DefaultNullStrings, NestedJSON, keyOrder, nil)
+}
+
+// fromMaps is FromMaps, which orders the keys not in keyOrder by their
+// index in seen, or alphabetically if seen is nil.
+func fromMaps(rows []map[string]interface{}, nulls NullStrings, nested NestedMode, keyOrder, seen []string) Content {
+	headers := append([]string(nil), keyOrder...)
+
+	var rest []string
+	for _, header := range collectHeader(rows) {
+		if !containsString(keyOrder, header) {
+			rest = append(rest, header)
 		}
-		outputRows = append(outputRows, outputRow)
+	}
+	if seen != nil {
+		sortByRank(rest, seen)
+	} else {
+		sort.Strings(rest)
+	}
+	headers = append(headers, rest...)
+
+	var outputRows [][]string
+	for _, row := range rows {
+		outputRows = append(outputRows, expandRow(row, headers, nested, nulls)...)
 	}
 
 	return Content{
 		header: headers,
 		rows:   outputRows,
-	}, nil
+	}
 }
 
-func formatTable(c Content, w io.Writer) {
-	fmt.Printf("\n🕸️  TABLE RESULT (Rows:%d)\n", len(c.rows))
-	table := tablewriter.NewWriter(w)
-	table.SetHeader(c.header)
-	table.AppendBulk(c.rows)
-	table.Render()
+func formatTable(c Content, w io.Writer, o *options) error {
+	rd, titled := withTitle(o.renderer, o.title, o.caption)
+	if o.nulls != nil {
+		rd = rendererWithNullStrings(rd, o.nulls)
+	}
+
+	if o.banner {
+		switch {
+		case o.title == "":
+			fmt.Fprintf(w, "\n🕸️  TABLE RESULT (Rows:%d)\n", len(c.rows))
+		case titled:
+			fmt.Fprintln(w)
+		default:
+			fmt.Fprintf(w, "\n%s\n", o.title)
+		}
+	}
+
+	return rd.Render(c, w)
 }
 
 func collectHeader(rows []map[string]interface{}) []string {
@@ -138,7 +356,6 @@ func collectHeader(rows []map[string]interface{}) []string {
 	}
 
 	var out []string
-	out = append(out, "#")
 	for header := range headerMap {
 		out = append(out, header)
 	}
@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTSVField(t *testing.T) {
+	tests := []struct {
+		mode  Sanitize
+		value string
+		want  string
+	}{
+		{SanitizeNone, "plain", "plain"},
+		{SanitizeNone, "", ""},
+		{SanitizeNone, "a\tb", "\"a\tb\""},
+		{SanitizeNone, "line\nbreak", "\"line\nbreak\""},
+		{SanitizeNone, "carriage\rreturn", "\"carriage\rreturn\""},
+		{SanitizeNone, `say "hi"`, `"say ""hi"""`},
+		{SanitizeNone, `"quoted"`, `"""quoted"""`},
+		{SanitizeNone, "=SUM(A1:A2)", "=SUM(A1:A2)"},
+		{SanitizeNone, "@cmd", "@cmd"},
+
+		{SanitizeStrip, "a\tb", "a   b"},
+		{SanitizeStrip, "line\nbreak", "\"line\nbreak\""},
+		{SanitizeStrip, "carriage\r\nreturn", "\"carriage\nreturn\""},
+		{SanitizeStrip, `say "hi"`, `"say ""hi"""`},
+		{SanitizeStrip, "=SUM(A1:A2)", "'=SUM(A1:A2)"},
+		{SanitizeStrip, "+x", "'+x"},
+		{SanitizeStrip, "-x", "'-x"},
+		{SanitizeStrip, "@cmd", "'@cmd"},
+		{SanitizeStrip, `=HYPERLINK("x")`, `"'=HYPERLINK(""x"")"`},
+		{SanitizeStrip, "-1.5", "-1.5"},
+		{SanitizeStrip, "+3", "+3"},
+		{SanitizeStrip, "a=b", "a=b"},
+
+		{SanitizeEscape, "a\tb", `a\tb`},
+		{SanitizeEscape, "line\nbreak", "\"line\nbreak\""},
+		{SanitizeEscape, `say "hi"`, `"say ""hi"""`},
+		{SanitizeEscape, "=SUM(A1:A2)", "'=SUM(A1:A2)"},
+		{SanitizeEscape, "+x", "'+x"},
+		{SanitizeEscape, "-x", "'-x"},
+		{SanitizeEscape, "@cmd", "'@cmd"},
+		{SanitizeEscape, "-1.5", "-1.5"},
+	}
+
+	for _, tt := range tests {
+		if got := tsvField(tt.value, tt.mode); got != tt.want {
+			t.Errorf("tsvField(%q, %v) = %q, want %q", tt.value, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTSVRecord(t *testing.T) {
+	var b bytes.Buffer
+	writeTSVRecord(&b, []string{"a", "b c", "d"}, SanitizeNone)
+	if got, want := b.String(), "a\tb c\td\n"; got != want {
+		t.Errorf("got %q, want %q without a trailing tab", got, want)
+	}
+}
+
+func TestTSVRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		mode Sanitize
+		in   []string
+		want []string
+	}{
+		{
+			name: "plain",
+			mode: SanitizeNone,
+			in:   []string{"apple", "1.5", ""},
+			want: []string{"apple", "1.5", ""},
+		},
+		{
+			name: "tab",
+			mode: SanitizeNone,
+			in:   []string{"a\tb", "c"},
+			want: []string{"a\tb", "c"},
+		},
+		{
+			name: "line breaks",
+			mode: SanitizeNone,
+			in:   []string{"one\ntwo", "three\r\nfour"},
+			// like spreadsheets, csv readers turn CRLF into LF
+			want: []string{"one\ntwo", "three\nfour"},
+		},
+		{
+			name: "quotes",
+			mode: SanitizeNone,
+			in:   []string{`say "hi"`, `"`, `a"b"`},
+			want: []string{`say "hi"`, `"`, `a"b"`},
+		},
+		{
+			name: "formulas",
+			mode: SanitizeStrip,
+			in:   []string{"=1+2", "-3", "@a", "-b"},
+			want: []string{"'=1+2", "-3", "'@a", "'-b"},
+		},
+		{
+			name: "sanitized",
+			mode: SanitizeStrip,
+			in:   []string{"\x1b[31mred\x1b[0m", "multi\nline"},
+			want: []string{"red", "multi\nline"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make([]string, len(tt.in))
+			for i := range header {
+				header[i] = "col" + string(rune('a'+i))
+			}
+
+			var b bytes.Buffer
+			writeTSVRecord(&b, header, tt.mode)
+			writeTSVRecord(&b, tt.in, tt.mode)
+			c, err := (&CSVParser{Delimiter: '\t'}).Parse(&b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(c.rows) != 1 {
+				t.Fatalf("got %d rows, want 1", len(c.rows))
+			}
+			for j, want := range tt.want {
+				if got := c.At(0, j); got != want {
+					t.Errorf("value %d = %q, want %q", j, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatPbcopy(t *testing.T) {
+	var b bytes.Buffer
+	if err := FormatPbcopy(&CSVParser{}, strings.NewReader("name\napple\n"), &b, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := b.String(); !strings.Contains(got, "Rows:1") || !strings.Contains(got, "| apple |") {
+		t.Errorf("got\n%s\nwant the row count and the table", got)
+	}
+}
+
+func TestFormatMasksLast(t *testing.T) {
+	opts := []Option{
+		WithRenderer(&CSVRenderer{}),
+		WithMask(map[string]MaskMode{"customer": MaskFull}),
+		WithFilter(`customer == "alice"`),
+		WithSort("qty desc"),
+		WithComputedColumn("initial", "customer == 'alice'"),
+	}
+
+	var b bytes.Buffer
+	if err := Format(&CSVParser{}, strings.NewReader(ordersCSV), &b, opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "id,customer,price,qty,initial\n3,****,7,4,true\n1,****,10,2,true\n"
+	if got := b.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	// masks name the columns before they are swapped
+	b.Reset()
+	opts = []Option{WithRenderer(&CSVRenderer{}), WithTranspose(), WithMask(map[string]MaskMode{"name": MaskFull})}
+	if err := Format(&CSVParser{}, strings.NewReader("name,qty\npear,1\n"), &b, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); !strings.Contains(got, "name,****") || strings.Contains(got, "pear") {
+		t.Errorf("got\n%s\nwant the transposed masked name", got)
+	}
+}
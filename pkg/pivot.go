@@ -0,0 +1,94 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Pivot returns a cross table of the Content, e.g. of the errors by
+// service and day. Its first column holds the distinct values of the
+// index column, in the order they first appear, and every distinct
+// value of the columns column becomes a column, sorted by value. The
+// cells aggregate the values column of the rows with the index and
+// column value, cells without rows are empty. If values is empty, every
+// row counts as the value 1, so that Count and Sum count the rows.
+func Pivot(c Content, index, columns, values string, agg AggFunc) (Content, error) {
+	ii := c.columnIndex(index)
+	if ii < 0 {
+		return Content{}, fmt.Errorf("column %q does not exist", index)
+	}
+	ci := c.columnIndex(columns)
+	if ci < 0 {
+		return Content{}, fmt.Errorf("column %q does not exist", columns)
+	}
+	vi := -1
+	if values != "" {
+		if vi = c.columnIndex(values); vi < 0 {
+			return Content{}, fmt.Errorf("column %q does not exist", values)
+		}
+	}
+	if agg == nil {
+		agg = Count
+	}
+
+	var keys, names []string
+	rowOf, columnOf := map[string]int{}, map[string]int{}
+	for i := range c.rows {
+		if k := c.At(i, ii); !seen(rowOf, k) {
+			rowOf[k] = len(keys)
+			keys = append(keys, k)
+		}
+		if name := c.At(i, ci); !seen(columnOf, name) {
+			columnOf[name] = len(names)
+			names = append(names, name)
+		}
+	}
+
+	kind := detectSortKind(names)
+	sort.SliceStable(names, func(a, b int) bool {
+		return compareValues(names[a], names[b], kind) < 0
+	})
+	for j, name := range names {
+		columnOf[name] = j
+	}
+
+	cells := make([][][]string, len(keys))
+	for i := range cells {
+		cells[i] = make([][]string, len(names))
+	}
+	for i := range c.rows {
+		v := "1"
+		if vi >= 0 {
+			v = c.At(i, vi)
+		}
+		cell := &cells[rowOf[c.At(i, ii)]][columnOf[c.At(i, ci)]]
+		*cell = append(*cell, v)
+	}
+
+	rows := make([][]string, len(keys))
+	for i, k := range keys {
+		rows[i] = make([]string, len(names)+1)
+		rows[i][0] = k
+		for j, cell := range cells[i] {
+			if cell == nil {
+				continue
+			}
+			v, err := agg(cell)
+			if err != nil {
+				return Content{}, fmt.Errorf("%s %q, %s %q: %w", c.header[ii], k, c.header[ci], names[j], err)
+			}
+			rows[i][j+1] = v
+		}
+	}
+
+	return Content{
+		header: append([]string{c.header[ii]}, names...),
+		rows:   rows,
+	}, nil
+}
+
+// seen reports whether the key is in the map.
+func seen(m map[string]int, key string) bool {
+	_, ok := m[key]
+	return ok
+}
@@ -0,0 +1,30 @@
+package pkg
+
+import "testing"
+
+func TestPivot(t *testing.T) {
+	in := "service,day,errors\napi,tue,3\nweb,mon,1\napi,mon,2\napi,mon,4\n"
+
+	tests := []struct {
+		name   string
+		values string
+		agg    AggFunc
+		want   string
+	}{
+		{"sum", "errors", Sum, "service,mon,tue\napi,6,3\nweb,1,\n"},
+		{"max", "errors", Max, "service,mon,tue\napi,4,3\nweb,1,\n"},
+		{"count rows", "", Count, "service,mon,tue\napi,2,1\nweb,1,\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Pivot(parseCSV(t, in), "service", "day", tt.values, tt.agg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := toCSV(t, c); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
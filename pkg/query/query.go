@@ -0,0 +1,132 @@
+// Package query runs SQL queries on Content using an in-memory SQLite
+// database. It is kept apart from package pkg, so that only programs
+// running queries link SQLite.
+package query
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+
+	// the in-memory database of Query
+	_ "modernc.org/sqlite"
+)
+
+// Null is the text of NULL values of the results of Query.
+const Null = "NULL"
+
+// Query runs a SQL query on the tables, which are loaded by name into
+// an in-memory SQLite database, e.g. to join or aggregate several
+// inputs:
+//
+//	c, err := query.Query("select name, count(*) from users group by name", map[string]pkg.Content{"users": users})
+//
+// Columns of integers or numbers, see Content.Types, are stored as
+// INTEGER or REAL, so that they are compared and aggregated as numbers,
+// all other columns as TEXT. Empty values and the texts of
+// pkg.DefaultNullStrings and Null are stored as NULL. NULL values of
+// the result are Null.
+func Query(query string, tables map[string]pkg.Content) (pkg.Content, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return pkg.Content{}, err
+	}
+	defer db.Close()
+	// every connection opens a database of its own
+	db.SetMaxOpenConns(1)
+
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := loadTable(db, name, tables[name]); err != nil {
+			return pkg.Content{}, fmt.Errorf("table %q: %w", name, err)
+		}
+	}
+
+	return pkg.QuerySQL(db, query)
+}
+
+// loadTable creates the table and inserts the rows of the Content.
+func loadTable(db *sql.DB, name string, c pkg.Content) error {
+	header := c.Header()
+	types := c.Types()
+	columns := make([]string, len(header))
+	for i, column := range header {
+		columns[i] = identifier(column) + " " + typeName(types[i])
+	}
+
+	if _, err := db.Exec("CREATE TABLE " + identifier(name) + " (" + strings.Join(columns, ", ") + ")"); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(header)), ", ")
+	stmt, err := tx.Prepare("INSERT INTO " + identifier(name) + " VALUES (" + placeholders + ")")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	args := make([]interface{}, len(header))
+	for i := range c.Rows() {
+		for j := range args {
+			args[j] = sqlValue(c.At(i, j), types[j])
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return fmt.Errorf("row %d: %w", i+1, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// identifier quotes the name of a table or column.
+func identifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// typeName returns the SQLite type of a column of the type.
+func typeName(t pkg.ColumnType) string {
+	switch t {
+	case pkg.TypeInt:
+		return "INTEGER"
+	case pkg.TypeFloat:
+		return "REAL"
+	}
+
+	return "TEXT"
+}
+
+// sqlValue converts the value to the argument inserted into a column of
+// the type.
+func sqlValue(value string, t pkg.ColumnType) interface{} {
+	if value == "" || value == pkg.DefaultNullStrings.Null || value == pkg.DefaultNullStrings.Missing || value == Null {
+		return nil
+	}
+
+	switch t {
+	case pkg.TypeInt:
+		if n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+			return n
+		}
+	case pkg.TypeFloat:
+		if n, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return n
+		}
+	}
+
+	return value
+}
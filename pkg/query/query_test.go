@@ -0,0 +1,129 @@
+package query
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+)
+
+// toCSV renders the Content as csv, to compare it with the expected
+// document.
+func toCSV(t *testing.T, c pkg.Content) string {
+	t.Helper()
+
+	var b bytes.Buffer
+	if err := (&pkg.CSVRenderer{}).Render(c, &b); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.String()
+}
+
+func TestQuery(t *testing.T) {
+	tables := map[string]pkg.Content{
+		"my fruits": pkg.NewContent([]string{"id", `unit "price"`, "name"}, [][]string{
+			{"10", "1.5", "apple"},
+			{"9", "2", "pear"},
+			{"11", "", "<nil>"},
+		}),
+		"stock": pkg.NewContent([]string{"id", "qty"}, [][]string{
+			{"9", "3"},
+			{"10", "x"},
+		}),
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "quoted names",
+			query: `select "unit ""price""" from "my fruits" where name = 'pear'`,
+			want:  "\"unit \"\"price\"\"\"\n2\n",
+		},
+		{
+			name:  "type affinity",
+			query: `select typeof(id), typeof("unit ""price"""), typeof(name) from "my fruits" limit 1`,
+			want:  "typeof(id),\"typeof(\"\"unit \"\"\"\"price\"\"\"\"\"\")\",typeof(name)\ninteger,real,text\n",
+		},
+		{
+			name:  "numbers sorted as numbers",
+			query: `select id from "my fruits" order by id`,
+			want:  "id\n9\n10\n11\n",
+		},
+		{
+			name:  "text column of numbers and text",
+			query: `select typeof(qty) from stock limit 1`,
+			want:  "typeof(qty)\ntext\n",
+		},
+		{
+			name:  "nulls",
+			query: `select id, name from "my fruits" where name is null and "unit ""price""" is null`,
+			want:  "id,name\n11,NULL\n",
+		},
+		{
+			name:  "join",
+			query: `select name, qty from "my fruits" join stock using (id) order by name`,
+			want:  "name,qty\napple,x\npear,3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Query(tt.query, tables)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := toCSV(t, c); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		tables map[string]pkg.Content
+		want   string
+	}{
+		{
+			name:  "syntax error",
+			query: "selec 1",
+			want:  "syntax error",
+		},
+		{
+			name:  "unknown table",
+			query: "select * from missing",
+			want:  "no such table: missing",
+		},
+		{
+			name:   "unknown column",
+			query:  "select price from t",
+			tables: map[string]pkg.Content{"t": pkg.NewContent([]string{"id"}, nil)},
+			want:   "no such column: price",
+		},
+		{
+			name:   "table not loaded",
+			query:  "select * from t",
+			tables: map[string]pkg.Content{"t": pkg.NewContent([]string{"id", "ID"}, nil)},
+			want:   `table "t": `,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Query(tt.query, tt.tables)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
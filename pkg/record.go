@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// RecordRenderer is a renderer implementation that prints every row as
+// a block of "column: value" lines, like the vertical output of the
+// MySQL client. It is easier to read than a table for wide rows.
+type RecordRenderer struct{}
+
+// Render writes the Content as records to the writer.
+func (rr *RecordRenderer) Render(c Content, w io.Writer) error {
+	width := 0
+	for _, name := range c.header {
+		if w := tablewriter.DisplayWidth(name); w > width {
+			width = w
+		}
+	}
+
+	// Continuation lines of multi-line values are indented to the
+	// start of the value.
+	indent := "\n" + strings.Repeat(" ", width+2)
+
+	bw := bufio.NewWriter(w)
+	for i := range c.rows {
+		fmt.Fprintf(bw, "%s %d. row %s\n", strings.Repeat("*", 27), i+1, strings.Repeat("*", 27))
+		for j, name := range c.header {
+			value := strings.ReplaceAll(c.At(i, j), "\n", indent)
+			fmt.Fprintf(bw, "%s: %s\n", tablewriter.PadLeft(name, " ", width), value)
+		}
+	}
+
+	return bw.Flush()
+}
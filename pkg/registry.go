@@ -0,0 +1,237 @@
+package pkg
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The registry maps format names to parser and renderer constructors,
+// so that packages can plug in further formats and tools can resolve
+// formats given by name, file extension or MIME type.
+var registry = struct {
+	sync.RWMutex
+	parsers    map[string]func() Parser
+	renderers  map[string]func() Renderer
+	extensions map[string]string
+	mimeTypes  map[string]string
+}{
+	parsers:    map[string]func() Parser{},
+	renderers:  map[string]func() Renderer{},
+	extensions: map[string]string{},
+	mimeTypes:  map[string]string{},
+}
+
+func init() {
+	RegisterParser("csv", func() Parser { return &CSVParser{} })
+	RegisterParser("tsv", func() Parser { return &CSVParser{Delimiter: '\t'} })
+	RegisterParser("json", func() Parser { return &JSONParser{} })
+	RegisterParser("yaml", func() Parser { return &YAMLParser{} })
+	RegisterParser("toml", func() Parser { return &TOMLParser{} })
+	RegisterParser("xml", func() Parser { return &XMLParser{} })
+	RegisterParser("markdown", func() Parser { return &MarkdownParser{} })
+	RegisterParser("fixed", func() Parser { return &FixedWidthParser{} })
+
+	RegisterRenderer("table", func() Renderer { return &TableRenderer{} })
+	RegisterRenderer("markdown", func() Renderer { return &MarkdownRenderer{} })
+	RegisterRenderer("html", func() Renderer { return &HTMLRenderer{} })
+	RegisterRenderer("record", func() Renderer { return &RecordRenderer{} })
+	RegisterRenderer("csv", func() Renderer { return &CSVRenderer{} })
+	RegisterRenderer("tsv", func() Renderer { return &CSVRenderer{Delimiter: '\t'} })
+	RegisterRenderer("json", func() Renderer { return &JSONRenderer{} })
+	RegisterRenderer("ndjson", func() Renderer { return &NDJSONRenderer{} })
+	RegisterRenderer("sql", func() Renderer { return &SQLRenderer{} })
+	RegisterRenderer("latex", func() Renderer { return &LaTeXRenderer{} })
+	RegisterRenderer("rst", func() Renderer { return &RSTRenderer{} })
+	RegisterRenderer("org", func() Renderer { return &OrgRenderer{} })
+	RegisterRenderer("jira", func() Renderer { return &JiraRenderer{} })
+	RegisterRenderer("confluence", func() Renderer { return &ConfluenceRenderer{} })
+	RegisterRenderer("mediawiki", func() Renderer { return &MediaWikiRenderer{} })
+
+	for ext, name := range map[string]string{
+		".csv":      "csv",
+		".tsv":      "tsv",
+		".tab":      "tsv",
+		".json":     "json",
+		".ndjson":   "ndjson",
+		".jsonl":    "ndjson",
+		".yaml":     "yaml",
+		".yml":      "yaml",
+		".toml":     "toml",
+		".xml":      "xml",
+		".md":       "markdown",
+		".markdown": "markdown",
+		".html":     "html",
+		".htm":      "html",
+		".sql":      "sql",
+		".tex":      "latex",
+		".rst":      "rst",
+		".org":      "org",
+		".wiki":     "mediawiki",
+	} {
+		RegisterExtension(ext, name)
+	}
+
+	for mimeType, name := range map[string]string{
+		"text/csv":                  "csv",
+		"text/tab-separated-values": "tsv",
+		"application/json":          "json",
+		"application/x-ndjson":      "ndjson",
+		"application/jsonl":         "ndjson",
+		"application/yaml":          "yaml",
+		"application/x-yaml":        "yaml",
+		"text/yaml":                 "yaml",
+		"application/toml":          "toml",
+		"application/xml":           "xml",
+		"text/xml":                  "xml",
+		"text/markdown":             "markdown",
+		"text/html":                 "html",
+		"application/sql":           "sql",
+		"application/x-latex":       "latex",
+		"text/x-rst":                "rst",
+	} {
+		RegisterMIMEType(mimeType, name)
+	}
+}
+
+// RegisterParser makes a parser available under the given name. The
+// constructor is called for every lookup, so that the returned parsers
+// can be configured independently. A parser registered under an existing
+// name replaces it.
+func RegisterParser(name string, newParser func() Parser) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.parsers[strings.ToLower(name)] = newParser
+}
+
+// RegisterRenderer makes a renderer available under the given name, in
+// the same way as RegisterParser.
+func RegisterRenderer(name string, newRenderer func() Renderer) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.renderers[strings.ToLower(name)] = newRenderer
+}
+
+// RegisterExtension associates a file extension like ".csv" with the
+// name of a format.
+func RegisterExtension(ext, name string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	registry.extensions[strings.ToLower(ext)] = strings.ToLower(name)
+}
+
+// RegisterMIMEType associates a MIME type like "text/csv" with the name
+// of a format.
+func RegisterMIMEType(mimeType, name string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.mimeTypes[strings.ToLower(mimeType)] = strings.ToLower(name)
+}
+
+// NewParser returns a new parser for the named format. Besides the names
+// the parsers are registered with, file extensions without the leading
+// dot are accepted, e.g. "yml" or "md".
+func NewParser(name string) (Parser, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if newParser, ok := registry.parsers[resolveFormat(name)]; ok {
+		return newParser(), nil
+	}
+
+	return nil, fmt.Errorf("%q is not a supported parser", name)
+}
+
+// NewRenderer returns a new renderer for the named format, see
+// NewParser.
+func NewRenderer(name string) (Renderer, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if newRenderer, ok := registry.renderers[resolveFormat(name)]; ok {
+		return newRenderer(), nil
+	}
+
+	return nil, fmt.Errorf("%q is not a supported renderer", name)
+}
+
+// resolveFormat returns the format name for a name or an extension.
+// The caller must hold the registry lock.
+func resolveFormat(name string) string {
+	name = strings.ToLower(name)
+	if _, ok := registry.parsers[name]; ok {
+		return name
+	}
+	if _, ok := registry.renderers[name]; ok {
+		return name
+	}
+	if format, ok := registry.extensions["."+name]; ok {
+		return format
+	}
+
+	return name
+}
+
+// FormatByExtension returns the format registered for the extension of
+// the file name, e.g. "yaml" for "config.yml".
+func FormatByExtension(filename string) (string, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	name, ok := registry.extensions[strings.ToLower(filepath.Ext(filename))]
+	return name, ok
+}
+
+// FormatByMIMEType returns the format registered for the media type of
+// a Content-Type header value, e.g. "json" for
+// "application/json; charset=utf-8".
+func FormatByMIMEType(contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+
+	registry.RLock()
+	defer registry.RUnlock()
+
+	name, ok := registry.mimeTypes[mediaType]
+	return name, ok
+}
+
+// ParserNames returns the names of the registered parsers in
+// alphabetical order.
+func ParserNames() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return sortedKeys(registry.parsers)
+}
+
+// RendererNames returns the names of the registered renderers in
+// alphabetical order.
+func RendererNames() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return sortedKeys(registry.renderers)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,123 @@
+package pkg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const fruitsCSV = "name,price\napple,1.5\npear,2\n"
+
+func TestParsers(t *testing.T) {
+	inputs := map[string]string{
+		"csv":      fruitsCSV,
+		"tsv":      "name\tprice\napple\t1.5\npear\t2\n",
+		"json":     `[{"name": "apple", "price": 1.5}, {"name": "pear", "price": 2}]`,
+		"yaml":     "- name: apple\n  price: 1.5\n- name: pear\n  price: 2\n",
+		"toml":     "[[fruit]]\nname = \"apple\"\nprice = 1.5\n[[fruit]]\nname = \"pear\"\nprice = 2\n",
+		"xml":      "<fruits><fruit><name>apple</name><price>1.5</price></fruit><fruit><name>pear</name><price>2</price></fruit></fruits>",
+		"markdown": "| name | price |\n|---|---|\n| apple | 1.5 |\n| pear | 2 |\n",
+	}
+
+	for name, in := range inputs {
+		t.Run(name, func(t *testing.T) {
+			p, err := NewParser(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := p.Parse(strings.NewReader(in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := toCSV(t, c); got != fruitsCSV {
+				t.Errorf("got\n%s\nwant\n%s", got, fruitsCSV)
+			}
+		})
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"csv", fruitsCSV},
+		{"tsv", "name\tprice\napple\t1.5\npear\t2\n"},
+		{"ndjson", "{\"name\":\"apple\",\"price\":\"1.5\"}\n{\"name\":\"pear\",\"price\":\"2\"}\n"},
+		{"markdown", "| name  | price |\n| ----- | ----: |\n| apple |   1.5 |\n| pear  |     2 |\n"},
+		{"jira", "||name||price||\n|apple|1.5|\n|pear|2|\n"},
+		{"org", "| name  | price |\n|-------+-------|\n| apple |   1.5 |\n| pear  |     2 |\n"},
+		{"latex", "\\begin{tabular}{lr}\n\\hline\nname & price \\\\\n\\hline\napple & 1.5 \\\\\npear & 2 \\\\\n\\hline\n\\end{tabular}\n"},
+		{"sql", "CREATE TABLE \"data\" (\n  \"name\" TEXT,\n  \"price\" REAL\n);\n" +
+			"INSERT INTO \"data\" (\"name\", \"price\") VALUES ('apple', 1.5);\n" +
+			"INSERT INTO \"data\" (\"name\", \"price\") VALUES ('pear', 2);\n"},
+	}
+
+	c := parseCSV(t, fruitsCSV)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRenderer(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b bytes.Buffer
+			if err := r.Render(c, &b); err != nil {
+				t.Fatal(err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRoundTrip renders tables with tricky values and parses them back
+// with the parser of the same format.
+func TestRoundTrip(t *testing.T) {
+	in := "name,note\n\"a, b\",\"say \"\"hi\"\"\"\nc,\"two\nlines\"\nd,\n"
+	c := parseCSV(t, in)
+
+	for _, format := range []string{"csv", "tsv", "json"} {
+		t.Run(format, func(t *testing.T) {
+			r, err := NewRenderer(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, err := NewParser(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b bytes.Buffer
+			if err := r.Render(c, &b); err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.Parse(&b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := toCSV(t, got); got != in {
+				t.Errorf("got\n%s\nwant\n%s", got, in)
+			}
+		})
+	}
+}
+
+func TestFormatByExtension(t *testing.T) {
+	tests := map[string]string{
+		"a.csv":  "csv",
+		"a.tsv":  "tsv",
+		"a.JSON": "json",
+		"a.yml":  "yaml",
+	}
+
+	for name, want := range tests {
+		if got, ok := FormatByExtension(name); !ok || got != want {
+			t.Errorf("FormatByExtension(%q) = %q, %v, want %q", name, got, ok, want)
+		}
+	}
+	if _, ok := FormatByExtension("a.unknown"); ok {
+		t.Error("unknown extensions should not be detected")
+	}
+}
@@ -0,0 +1,413 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Renderer describes an interface to render our intermediate Content
+// form into an arbitrary document.
+type Renderer interface {
+	Render(Content, io.Writer) error
+}
+
+// Convert converts the content of the reader using the supplied parser
+// and writes it to the writer using the supplied renderer.
+func Convert(p Parser, rd Renderer, r io.Reader, w io.Writer) error {
+	c, err := p.Parse(r)
+	if err != nil {
+		return err
+	}
+
+	return rd.Render(c, w)
+}
+
+// TableRenderer is a renderer implementation that draws text tables.
+type TableRenderer struct {
+	// MaxWidth is the width at which cell values are wrapped between
+	// words, the tablewriter default is used if it is unset. Line breaks
+	// in values are always kept.
+	MaxWidth int
+	// Style selects the borders, StyleASCII is used by default.
+	Style Style
+	// Theme colors the values. It is only applied if ColorEnabled
+	// reports true for the writer, unless ForceColor is set.
+	Theme *Theme
+	// ForceColor applies the Theme to any writer.
+	ForceColor bool
+	// Align sets the alignment of columns by name. Columns default to
+	// AlignAuto, which right-aligns numeric columns on the decimal point.
+	Align map[string]Alignment
+	// MaxColumnWidth limits the display width of cell values, longer
+	// values are handled according to Overflow. It is unlimited if it is
+	// unset.
+	MaxColumnWidth int
+	// Width is the maximum width of the table, e.g. the TerminalWidth.
+	// The widest columns are wrapped to fit the table into it, and if
+	// that is not enough, the last columns are left out. The width is
+	// unlimited if it is unset.
+	Width int
+	// Footer aggregates the values of columns by name into a footer
+	// row, e.g. totals. The footer is left out if it is unset.
+	Footer map[string]AggFunc
+	// Title is written on a line above the table, Caption on a line
+	// below it.
+	Title, Caption string
+	// Overflow selects how values wider than MaxColumnWidth are shown,
+	// they are truncated by default. OverflowWrap wraps them, breaking up
+	// words if needed, so the column never grows wider.
+	Overflow Overflow
+	// PageSize splits the rows into tables of at most PageSize rows,
+	// which repeat the header and share the column widths. The footer
+	// is only drawn below the last page. All rows are drawn in a single
+	// table if it is unset.
+	PageSize int
+	// Nulls are the texts of values which are not set, which are colored
+	// with Theme.Null and ignored when inferring the column types. The
+	// texts of DefaultNullStrings and "NULL" are used if it is unset.
+	Nulls *NullStrings
+	// Sanitize selects how control characters and ANSI escape sequences
+	// in values are printed, they are removed by default.
+	Sanitize Sanitize
+	// NumberFormats format the numbers of columns by name, including the
+	// Footer, which aggregates the values as they are.
+	NumberFormats map[string]NumberFormat
+	// Locale selects the decimal mark and thousands separator of the
+	// NumberFormats, e.g. "de" or "fr-CH". English ones are used if it is
+	// unset.
+	Locale string
+	// Highlights color the cells of matching rows. Like the Theme, they
+	// are only applied if ColorEnabled reports true for the writer,
+	// unless ForceColor is set, and override the colors of the Theme.
+	Highlights []Highlight
+	// Bars draw the numbers of columns by name as bars next to their
+	// values, relative to the largest number of the column.
+	Bars map[string]BarStyle
+	// BarWidth is the width of the bars of BarHorizontal, 10 is used if
+	// it is unset.
+	BarWidth int
+}
+
+// Render writes the Content as a text table to the writer.
+func (t *TableRenderer) Render(c Content, w io.Writer) error {
+	colored := t.ForceColor || ColorEnabled(w)
+	theme := t.Theme
+	if !colored {
+		theme = nil
+	}
+
+	c.types = c.typesWith(t.Nulls)
+	align, err := columnAlignments(c, t.Align)
+	if err != nil {
+		return err
+	}
+
+	footer, err := footerRow(c, t.Footer)
+	if err != nil {
+		return err
+	}
+
+	highlighted, err := highlightCells(c, t.Highlights)
+	if err != nil {
+		return err
+	}
+	if !colored {
+		highlighted = nil
+	}
+
+	// the footer is laid out with the rows and removed afterwards
+	rows := make([][]string, len(c.rows), len(c.rows)+1)
+	for i, row := range c.rows {
+		if err := c.canceled(i); err != nil {
+			return err
+		}
+		rows[i] = sanitizeRow(row, t.Sanitize)
+	}
+	if footer != nil {
+		rows = append(rows, footer)
+	}
+
+	formats, err := columnNumberFormats(c, t.NumberFormats)
+	if err != nil {
+		return err
+	}
+	decimal, group := localeSeparators(t.Locale)
+	for _, row := range rows {
+		for j, f := range formats {
+			if f != nil && j < len(row) {
+				row[j] = f.format(row[j], decimal, group)
+			}
+		}
+	}
+
+	var notes []string
+	if t.MaxColumnWidth > 0 && t.Overflow != OverflowWrap {
+		notes = truncateRows(rows, t.MaxColumnWidth, t.Overflow)
+	}
+
+	for i, a := range align {
+		if a == AlignRight && t.Align[c.header[i]] == AlignAuto {
+			mark := "."
+			if formats[i] != nil {
+				mark = decimal
+			}
+			alignDecimals(rows, i, mark)
+		}
+	}
+
+	bars, err := columnBars(c, t.Bars)
+	if err != nil {
+		return err
+	}
+	for i, style := range bars {
+		if style != nil {
+			addBars(rows, c.column(i), i, *style, t.BarWidth, align[i])
+		}
+	}
+
+	header, widths, hidden := t.layout(sanitizeRow(c.header, t.Sanitize), rows)
+	align = align[:len(header)]
+	if footer != nil {
+		footer = rows[len(rows)-1]
+		rows = rows[:len(rows)-1]
+
+		// tablewriter aligns all footer values alike
+		for j, value := range footer {
+			switch align[j] {
+			case AlignRight:
+				footer[j] = tablewriter.PadLeft(value, " ", widths[j])
+			case AlignCenter:
+				footer[j] = tablewriter.Pad(value, " ", widths[j])
+			}
+		}
+	}
+
+	if theme != nil || highlighted != nil {
+		types := c.Types()
+		for i, row := range rows {
+			if err := c.canceled(i); err != nil {
+				return err
+			}
+			colors := make([]tablewriter.Colors, len(row))
+			if theme != nil {
+				colors = theme.rowColors(i, c.rows[i], types, t.Nulls)
+			}
+			for j := range row {
+				if j >= len(colors) {
+					continue
+				}
+				color := colors[j]
+				if highlighted != nil && j < len(highlighted[i]) && highlighted[i][j] != nil {
+					// later parameters take precedence
+					color = append(append([]int(nil), color...), highlightSGR(highlighted[i][j])...)
+				}
+				row[j] = colorLines(row[j], color)
+			}
+		}
+	}
+
+	if t.Title != "" {
+		if _, err := fmt.Fprintln(w, t.Title); err != nil {
+			return err
+		}
+	}
+
+	// pages share the column widths, a single table needs none
+	pageSize, pageWidths := len(rows), []int(nil)
+	if t.PageSize > 0 && t.PageSize < len(rows) {
+		pageSize, pageWidths = t.PageSize, widths
+	}
+	for start := 0; ; start += pageSize {
+		end := start + pageSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		if start > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		var pageFooter []string
+		if end == len(rows) {
+			pageFooter = footer
+		}
+
+		if err := t.draw(header, rows[start:end], pageFooter, pageWidths, align, w, theme); err != nil {
+			return err
+		}
+		if end == len(rows) {
+			break
+		}
+	}
+
+	if t.Caption != "" {
+		if _, err := fmt.Fprintln(w, t.Caption); err != nil {
+			return err
+		}
+	}
+
+	if err := writeHiddenColumns(w, hidden); err != nil {
+		return err
+	}
+
+	return writeFootnotes(w, notes)
+}
+
+// headerText returns the column name as shown in the header of text
+// tables, upper-cased. Unlike tablewriter.Title, it keeps the dots and
+// underscores of names like "user.name" or "__source".
+func headerText(name string) string {
+	return strings.ToUpper(name)
+}
+
+// headerTexts returns the headerText of every name.
+func headerTexts(names []string) []string {
+	texts := make([]string, len(names))
+	for i, name := range names {
+		texts[i] = headerText(name)
+	}
+
+	return texts
+}
+
+// layout wraps the header and the values of the rows in place and
+// fits them into the Width. It returns the wrapped header, the widths
+// of the columns and the names of the columns dropped to fit the table,
+// which are removed from the rows.
+func (t *TableRenderer) layout(names []string, rows [][]string) ([]string, []int, []string) {
+	// Values are wrapped here, as tablewriter separates the lines of
+	// multi-line values by empty lines and never breaks up words.
+	width, hard := tablewriter.MAX_ROW_WIDTH, false
+	if t.MaxWidth > 0 {
+		width = t.MaxWidth
+	}
+	if t.MaxColumnWidth > 0 && t.Overflow == OverflowWrap {
+		width, hard = t.MaxColumnWidth, true
+	}
+
+	header := make([]string, len(names))
+	widths := make([]int, len(names))
+	for i, name := range names {
+		header[i] = wrapText(headerText(name), width, false)
+		widths[i] = cellWidth(header[i])
+	}
+	for _, row := range rows {
+		for j, value := range row {
+			row[j] = wrapText(value, width, hard)
+			if j < len(widths) {
+				if w := cellWidth(row[j]); w > widths[j] {
+					widths[j] = w
+				}
+			}
+		}
+	}
+
+	if t.Width <= 0 || len(widths) == 0 {
+		return header, widths, nil
+	}
+
+	keep, fitted := fitWidths(widths, t.Width)
+	hidden := names[keep:]
+	header = header[:keep]
+	for i, w := range fitted {
+		if w < widths[i] {
+			header[i] = wrapText(header[i], w, true)
+		}
+	}
+	for i, row := range rows {
+		if len(row) > keep {
+			row = row[:keep]
+			rows[i] = row
+		}
+		for j, value := range row {
+			if fitted[j] < widths[j] {
+				row[j] = wrapText(value, fitted[j], true)
+			}
+		}
+	}
+
+	return header, fitted, hidden
+}
+
+// draw renders the table in the Style. The columns are at least as
+// wide as the widths, if they are given.
+func (t *TableRenderer) draw(header []string, rows [][]string, footer []string, widths []int, align []Alignment, w io.Writer, theme *Theme) error {
+	if t.Style == StyleASCII {
+		t.render(header, rows, footer, widths, align, w, theme)
+		return nil
+	}
+
+	var b strings.Builder
+	t.render(header, rows, footer, widths, align, &b, theme)
+	_, err := io.WriteString(w, restyle(b.String(), t.Style))
+
+	return err
+}
+
+func (t *TableRenderer) render(header []string, rows [][]string, footer []string, widths []int, align []Alignment, w io.Writer, theme *Theme) {
+	// tablewriter aligns every value on its own unless the alignment of
+	// the columns is set, which is also needed for colored numbers.
+	columnAlign := make([]int, len(align))
+	for i, a := range align {
+		columnAlign[i] = a.tablewriterAlignment()
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetAutoWrapText(false)
+	// the header is formatted by layout, the footer is left as it is
+	table.SetAutoFormatHeaders(false)
+	applyStyle(table, t.Style)
+	table.SetHeader(header)
+	table.SetColumnAlignment(columnAlign)
+	for i, width := range widths {
+		table.SetColMinWidth(i, width)
+	}
+	if theme != nil && theme.Header != nil && len(header) > 0 {
+		table.SetHeaderColor(theme.headerColors(len(header))...)
+	}
+	table.AppendBulk(rows)
+	if footer != nil {
+		table.SetFooter(footer)
+		if theme != nil && theme.Header != nil {
+			table.SetFooterColor(theme.headerColors(len(footer))...)
+		}
+	}
+
+	table.Render()
+}
+
+// footerRow aggregates the columns of the Content into a footer row.
+// Columns without an aggregate are left empty, except for the first,
+// which is labeled "Total". It returns nil if there are no aggregates.
+func footerRow(c Content, aggs map[string]AggFunc) ([]string, error) {
+	if len(aggs) == 0 {
+		return nil, nil
+	}
+
+	indices, funcs, err := resolveAggs(c, aggs)
+	if err != nil {
+		return nil, err
+	}
+
+	// tablewriter omits the borders of empty footer values
+	footer := make([]string, len(c.header))
+	for i := range footer {
+		footer[i] = " "
+	}
+	footer[0] = "Total"
+
+	for i, idx := range indices {
+		v, err := funcs[i](c.column(idx))
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", c.header[idx], err)
+		}
+		footer[idx] = v
+	}
+
+	return footer, nil
+}
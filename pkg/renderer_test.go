@@ -0,0 +1,39 @@
+package pkg
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTableRendererHeader(t *testing.T) {
+	const in = "user.name,__source,first_name,v1.0\nann,api,Ann,1\n"
+
+	formats := map[string]func(io.Reader, io.Writer) error{
+		"Format": func(r io.Reader, w io.Writer) error {
+			return Format(&CSVParser{}, r, w)
+		},
+		"FormatStream": func(r io.Reader, w io.Writer) error {
+			return FormatStream(&CSVParser{}, r, w, 1)
+		},
+		"FormatSpilled": func(r io.Reader, w io.Writer) error {
+			return FormatSpilled(&CSVParser{}, r, w, 1)
+		},
+	}
+
+	for name, format := range formats {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := format(strings.NewReader(in), &out); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, want := range []string{"USER.NAME", "__SOURCE", "FIRST_NAME", "V1.0"} {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("header lacks %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package pkg
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// RSTRenderer is a renderer implementation that emits reStructuredText
+// grid tables, as used by Sphinx. Values spanning multiple lines are
+// kept as multi-line cells.
+type RSTRenderer struct{}
+
+// Render writes the Content as a grid table to the writer.
+func (r *RSTRenderer) Render(c Content, w io.Writer) error {
+	header := rstLines(c.header, len(c.header))
+	rows := make([][][]string, len(c.rows))
+	for i, row := range c.rows {
+		rows[i] = rstLines(row, len(c.header))
+	}
+
+	widths := make([]int, len(c.header))
+	for _, row := range append([][][]string{header}, rows...) {
+		for i, lines := range row {
+			for _, line := range lines {
+				if width := tablewriter.DisplayWidth(line); width > widths[i] {
+					widths[i] = width
+				}
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	writeRSTBorder(bw, widths, "-")
+	writeRSTRow(bw, header, widths)
+	writeRSTBorder(bw, widths, "=")
+	for _, row := range rows {
+		writeRSTRow(bw, row, widths)
+		writeRSTBorder(bw, widths, "-")
+	}
+
+	return bw.Flush()
+}
+
+// rstLines splits every value of the row into its lines. The row is
+// padded to the given number of columns.
+func rstLines(row []string, columns int) [][]string {
+	out := make([][]string, columns)
+	for i := range out {
+		value := ""
+		if i < len(row) {
+			value = row[i]
+		}
+		out[i] = strings.Split(strings.ReplaceAll(value, "\r\n", "\n"), "\n")
+	}
+
+	return out
+}
+
+func writeRSTBorder(w *bufio.Writer, widths []int, fill string) {
+	w.WriteString("+")
+	for _, width := range widths {
+		w.WriteString(strings.Repeat(fill, width+2) + "+")
+	}
+	w.WriteString("\n")
+}
+
+func writeRSTRow(w *bufio.Writer, row [][]string, widths []int) {
+	height := 1
+	for _, lines := range row {
+		if len(lines) > height {
+			height = len(lines)
+		}
+	}
+
+	for line := 0; line < height; line++ {
+		w.WriteString("|")
+		for i, lines := range row {
+			value := ""
+			if line < len(lines) {
+				value = lines[line]
+			}
+			w.WriteString(" " + tablewriter.PadRight(value, " ", widths[i]) + " |")
+		}
+		w.WriteString("\n")
+	}
+}
@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"io"
+	"math/rand"
+	"sort"
+)
+
+// Sample returns a Transform which keeps n rows chosen at random, in
+// their original order. The rows are drawn from rnd, e.g.
+// rand.New(rand.NewSource(seed)) for the same sample on every run, or
+// from the default source if it is nil. All rows are kept if there are
+// at most n.
+func Sample(n int, rnd *rand.Rand) Transform {
+	return func(c Content) (Content, error) {
+		s := newReservoir(n, rnd)
+		for _, row := range c.rows {
+			s.add(row)
+		}
+
+		return Content{
+			header: c.header,
+			rows:   s.sample(),
+		}, nil
+	}
+}
+
+// SampleEvery returns a Transform which keeps every k-th row, starting
+// with the first.
+func SampleEvery(k int) Transform {
+	return func(c Content) (Content, error) {
+		if k <= 1 {
+			return c, nil
+		}
+
+		rows := make([][]string, 0, (len(c.rows)+k-1)/k)
+		for i := 0; i < len(c.rows); i += k {
+			rows = append(rows, c.rows[i])
+		}
+
+		return Content{
+			header: c.header,
+			rows:   rows,
+		}, nil
+	}
+}
+
+// SampleStream parses the document with the streaming parser and
+// returns n of its rows chosen at random like Sample, holding no more
+// than n rows in memory, so that huge documents can be sampled.
+func SampleStream(p StreamParser, r io.Reader, n int, rnd *rand.Rand) (Content, error) {
+	var header []string
+	s := newReservoir(n, rnd)
+
+	onHeader := func(h []string) error {
+		header = h
+		return nil
+	}
+	onRow := func(row []string) error {
+		s.add(row)
+		return nil
+	}
+
+	if err := p.ParseStream(r, onHeader, onRow); err != nil {
+		return Content{}, err
+	}
+
+	return Content{
+		header: header,
+		rows:   s.sample(),
+	}, nil
+}
+
+// reservoir draws a uniform sample of n rows from rows of unknown
+// number, see https://en.wikipedia.org/wiki/Reservoir_sampling.
+type reservoir struct {
+	n       int
+	rnd     *rand.Rand
+	seen    int
+	rows    [][]string
+	indices []int
+}
+
+func newReservoir(n int, rnd *rand.Rand) *reservoir {
+	if n < 0 {
+		n = 0
+	}
+
+	return &reservoir{n: n, rnd: rnd}
+}
+
+// add offers the next row to the sample.
+func (s *reservoir) add(row []string) {
+	i := s.seen
+	s.seen++
+
+	if len(s.rows) < s.n {
+		s.rows = append(s.rows, row)
+		s.indices = append(s.indices, i)
+		return
+	}
+
+	var j int
+	if s.rnd != nil {
+		j = s.rnd.Intn(i + 1)
+	} else {
+		j = rand.Intn(i + 1)
+	}
+	if j < s.n {
+		s.rows[j], s.indices[j] = row, i
+	}
+}
+
+// sample returns the sampled rows in the order they were added.
+func (s *reservoir) sample() [][]string {
+	order := make([]int, len(s.rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return s.indices[order[a]] < s.indices[order[b]]
+	})
+
+	rows := make([][]string, len(order))
+	for i, o := range order {
+		rows[i] = s.rows[o]
+	}
+
+	return rows
+}
@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// tabWidth is the distance of the tab stops tabs are expanded to.
+const tabWidth = 4
+
+// Sanitize selects how control characters and ANSI escape sequences in
+// values are printed, which would otherwise move the cursor, change the
+// colors of the terminal or break the layout of the table. Line breaks
+// are kept in any case.
+type Sanitize int
+
+const (
+	// SanitizeStrip removes ANSI escape sequences and control characters.
+	// Tabs are expanded to spaces, carriage returns are dropped or, if
+	// they do not precede a line feed, turned into line breaks.
+	SanitizeStrip Sanitize = iota
+	// SanitizeEscape shows control characters escaped, e.g. "\t" or
+	// "\x1b", so that escape sequences can be seen as they are.
+	SanitizeEscape
+	// SanitizeNone prints the values as they are.
+	SanitizeNone
+)
+
+var sanitizeNames = []string{"strip", "escape", "none"}
+
+// ParseSanitize returns the mode with the given name, i.e. "strip",
+// "escape" or "none".
+func ParseSanitize(name string) (Sanitize, error) {
+	for i, modeName := range sanitizeNames {
+		if strings.EqualFold(name, modeName) {
+			return Sanitize(i), nil
+		}
+	}
+
+	return SanitizeStrip, fmt.Errorf("unknown sanitize mode %q, supported modes: %s", name, strings.Join(sanitizeNames, ", "))
+}
+
+func (s Sanitize) String() string {
+	if s < 0 || int(s) >= len(sanitizeNames) {
+		return fmt.Sprintf("Sanitize(%d)", int(s))
+	}
+
+	return sanitizeNames[s]
+}
+
+// sanitize returns the value with its control characters removed or
+// escaped according to the mode.
+func sanitize(value string, mode Sanitize) string {
+	if mode == SanitizeNone || !hasControl(value) {
+		return value
+	}
+
+	var b strings.Builder
+	lineStart := 0
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		switch {
+		case r == '\n' || !isControl(r):
+			b.WriteString(value[i : i+size])
+			if r == '\n' {
+				lineStart = b.Len()
+			}
+		case mode == SanitizeEscape:
+			b.WriteString(escapeControl(r))
+		case r == '\t':
+			width := displayWidth(b.String()[lineStart:])
+			b.WriteString(strings.Repeat(" ", tabWidth-width%tabWidth))
+		case r == '\r':
+			if !strings.HasPrefix(value[i+size:], "\n") {
+				b.WriteByte('\n')
+				lineStart = b.Len()
+			}
+		case r == '\x1b':
+			size = ansiSequenceLen(value[i:])
+		}
+		i += size
+	}
+
+	return b.String()
+}
+
+// sanitizeRow returns a copy of the row with its values sanitized.
+func sanitizeRow(row []string, mode Sanitize) []string {
+	out := make([]string, len(row))
+	for j, value := range row {
+		out[j] = sanitize(value, mode)
+	}
+
+	return out
+}
+
+// hasControl reports whether the value holds a control character other
+// than a line feed. C1 control characters are encoded starting with
+// 0xc2.
+func hasControl(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if b := value[i]; (b < 0x20 && b != '\n') || b == 0x7f || b == 0xc2 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isControl reports whether the rune is a C0 or C1 control character.
+func isControl(r rune) bool {
+	return r < 0x20 || (r >= 0x7f && r <= 0x9f)
+}
+
+// escapeControl returns the escaped form of a control character.
+func escapeControl(r rune) string {
+	switch r {
+	case '\t':
+		return `\t`
+	case '\r':
+		return `\r`
+	}
+	if r < 0x80 {
+		return fmt.Sprintf(`\x%02x`, r)
+	}
+
+	return fmt.Sprintf(`\u%04x`, r)
+}
+
+// ansiSequenceLen returns the length of the ANSI escape sequence at the
+// start of s, which starts with an escape character: control sequences
+// like "\x1b[31m", operating system commands like the hyperlinks of
+// "\x1b]8;;url\x07", and two-character sequences. Unterminated
+// sequences extend to the end of the line.
+func ansiSequenceLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+
+	switch s[1] {
+	case '[':
+		// parameter and intermediate bytes up to a final byte
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+			if s[i] < 0x20 {
+				return i
+			}
+		}
+		return len(s)
+	case ']', 'P', '_', '^':
+		// strings terminated by BEL or ESC \
+		for i := 2; i < len(s); i++ {
+			switch {
+			case s[i] == '\a':
+				return i + 1
+			case s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\':
+				return i + 2
+			case s[i] == '\n':
+				return i
+			}
+		}
+		return len(s)
+	}
+
+	if s[1] < 0x20 {
+		return 1
+	}
+
+	_, size := utf8.DecodeRuneInString(s[1:])
+	return 1 + size
+}
@@ -0,0 +1,321 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema declares the columns a Content is expected to have, see
+// Validate.
+type Schema struct {
+	// Columns are the declared columns.
+	Columns []ColumnSchema `yaml:"columns"`
+	// Strict reports columns which are not declared.
+	Strict bool `yaml:"strict"`
+}
+
+// ColumnSchema declares a column of a Schema.
+type ColumnSchema struct {
+	// Name is the name of the column.
+	Name string `yaml:"name"`
+	// Type is the type of the values, i.e. "string", "int", "float",
+	// "bool" or "time", or the JSON Schema types "integer", "number" and
+	// "boolean". Empty values and null texts are of every type.
+	Type string `yaml:"type"`
+	// Required reports a missing column, like "required" of a JSON
+	// Schema.
+	Required bool `yaml:"required"`
+	// Nullable reports empty values and null texts if it is false. They
+	// are allowed if it is nil.
+	Nullable *bool `yaml:"nullable"`
+	// Pattern is a regular expression, which the non-empty values must
+	// match. It is not anchored, use ^ and $ to match whole values.
+	Pattern string `yaml:"pattern"`
+}
+
+// Violation is a value or column which does not match its Schema.
+type Violation struct {
+	// Row is the number of the row starting at 1, or 0 for violations
+	// of the header, e.g. missing columns.
+	Row int
+	// Column is the name of the column.
+	Column string
+	// Value is the offending value.
+	Value string
+	// Message describes the violation, e.g. "not of type int".
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Row == 0 {
+		return fmt.Sprintf("column %q: %s", v.Column, v.Message)
+	}
+
+	return fmt.Sprintf("row %d, column %q: %s", v.Row, v.Column, v.Message)
+}
+
+// jsonSchema is the subset of a JSON Schema of the rows understood by
+// ParseSchema.
+type jsonSchema struct {
+	Properties           yaml.Node `yaml:"properties"`
+	Required             []string  `yaml:"required"`
+	AdditionalProperties *bool     `yaml:"additionalProperties"`
+}
+
+// jsonProperty is a property of a jsonSchema.
+type jsonProperty struct {
+	Type     interface{} `yaml:"type"`
+	Format   string      `yaml:"format"`
+	Pattern  string      `yaml:"pattern"`
+	Nullable *bool       `yaml:"nullable"`
+}
+
+// ParseSchema reads a Schema in YAML or JSON, either listing the
+// columns:
+//
+//	strict: true
+//	columns:
+//	  - name: id
+//	    type: int
+//	    required: true
+//	    nullable: false
+//	  - name: email
+//	    pattern: ^[^@]+@[^@]+$
+//
+// or as a JSON Schema of the rows, whose properties are the columns. Of
+// the JSON Schema, only the "type", "format" (date and date-time are
+// times), "pattern" and "nullable" of the properties, "required" and
+// "additionalProperties" (false is strict) are used.
+func ParseSchema(r io.Reader) (Schema, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Schema{}, err
+	}
+
+	var doc struct {
+		Columns    []ColumnSchema `yaml:"columns"`
+		Strict     bool           `yaml:"strict"`
+		jsonSchema `yaml:",inline"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return Schema{}, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	s := Schema{Columns: doc.Columns, Strict: doc.Strict}
+	if doc.Properties.Kind != 0 {
+		if s, err = doc.jsonSchema.schema(); err != nil {
+			return Schema{}, err
+		}
+	}
+
+	for _, column := range s.Columns {
+		if column.Name == "" {
+			return Schema{}, fmt.Errorf("invalid schema: a column has no name")
+		}
+		if _, err := parseSchemaType(column.Type); err != nil {
+			return Schema{}, fmt.Errorf("invalid schema: column %q: %w", column.Name, err)
+		}
+		if _, err := regexp.Compile(column.Pattern); err != nil {
+			return Schema{}, fmt.Errorf("invalid schema: column %q: %w", column.Name, err)
+		}
+	}
+
+	return s, nil
+}
+
+// schema converts the JSON Schema, keeping the order of its properties.
+func (j jsonSchema) schema() (Schema, error) {
+	if j.Properties.Kind != yaml.MappingNode {
+		return Schema{}, fmt.Errorf("invalid schema: properties is not an object")
+	}
+
+	required := map[string]bool{}
+	for _, name := range j.Required {
+		required[name] = true
+	}
+
+	s := Schema{Strict: j.AdditionalProperties != nil && !*j.AdditionalProperties}
+	for i := 0; i+1 < len(j.Properties.Content); i += 2 {
+		name := j.Properties.Content[i].Value
+		var p jsonProperty
+		if err := j.Properties.Content[i+1].Decode(&p); err != nil {
+			return Schema{}, fmt.Errorf("invalid schema: property %q: %w", name, err)
+		}
+
+		column := ColumnSchema{
+			Name:     name,
+			Type:     p.typeName(),
+			Required: required[name],
+			Nullable: p.Nullable,
+			Pattern:  p.Pattern,
+		}
+		s.Columns = append(s.Columns, column)
+	}
+
+	return s, nil
+}
+
+// typeName returns the type of the property, ignoring "null" of a list
+// of types like ["string", "null"].
+func (p jsonProperty) typeName() string {
+	if p.Format == "date" || p.Format == "date-time" {
+		return TypeTime.String()
+	}
+
+	switch t := p.Type.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if name, ok := v.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseSchemaType returns the type of a ColumnSchema, TypeString if it
+// is empty.
+func parseSchemaType(name string) (ColumnType, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "string":
+		return TypeString, nil
+	case "int", "integer":
+		return TypeInt, nil
+	case "float", "number":
+		return TypeFloat, nil
+	case "bool", "boolean":
+		return TypeBool, nil
+	case "time":
+		return TypeTime, nil
+	}
+
+	return TypeString, fmt.Errorf("unknown type %q, supported types: string, int, float, bool, time", name)
+}
+
+// hasType reports whether the value is of the type.
+func hasType(value string, t ColumnType) bool {
+	switch t {
+	case TypeInt:
+		_, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		return err == nil
+	case TypeFloat:
+		_, ok := parseNumber(value)
+		return ok
+	case TypeBool:
+		return strings.EqualFold(value, "true") || strings.EqualFold(value, "false")
+	case TypeTime:
+		_, ok := parseTime(value)
+		return ok
+	}
+
+	return true
+}
+
+// Validate checks the Content against the Schema and returns its
+// violations, ordered by row and column. Empty values and the texts of
+// nulls, DefaultNullStrings if it is nil, only violate columns which
+// are not Nullable.
+func Validate(c Content, s Schema, nulls *NullStrings) ([]Violation, error) {
+	type check struct {
+		index   int
+		column  ColumnSchema
+		t       ColumnType
+		pattern *regexp.Regexp
+	}
+
+	var violations []Violation
+	var checks []check
+	declared := map[string]bool{}
+	for _, column := range s.Columns {
+		// columns are matched case-insensitively, like by columnIndex
+		declared[strings.ToLower(column.Name)] = true
+
+		i := c.columnIndex(column.Name)
+		if i < 0 {
+			if column.Required {
+				violations = append(violations, Violation{Column: column.Name, Message: "missing column"})
+			}
+			continue
+		}
+
+		t, err := parseSchemaType(column.Type)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: %w", column.Name, err)
+		}
+		var pattern *regexp.Regexp
+		if column.Pattern != "" {
+			if pattern, err = regexp.Compile(column.Pattern); err != nil {
+				return nil, fmt.Errorf("column %q: %w", column.Name, err)
+			}
+		}
+		checks = append(checks, check{index: i, column: column, t: t, pattern: pattern})
+	}
+
+	if s.Strict {
+		for _, name := range c.header {
+			if !declared[strings.ToLower(name)] {
+				violations = append(violations, Violation{Column: name, Message: "undeclared column"})
+			}
+		}
+	}
+
+	sort.SliceStable(checks, func(a, b int) bool {
+		return checks[a].index < checks[b].index
+	})
+
+	for i := range c.rows {
+		for _, check := range checks {
+			value := c.At(i, check.index)
+			var message string
+			switch {
+			case value == "" || isNull(value, nulls):
+				if n := check.column.Nullable; n != nil && !*n {
+					message = "missing value"
+				}
+			case !hasType(value, check.t):
+				message = "not of type " + check.t.String()
+			case check.pattern != nil && !check.pattern.MatchString(value):
+				message = fmt.Sprintf("does not match %q", check.column.Pattern)
+			}
+
+			if message != "" {
+				violations = append(violations, Violation{
+					Row:     i + 1,
+					Column:  check.column.Name,
+					Value:   value,
+					Message: message,
+				})
+			}
+		}
+	}
+
+	return violations, nil
+}
+
+// ViolationContent returns the violations as a table with the columns
+// row, column, value and problem. The row of violations of the header is
+// empty.
+func ViolationContent(violations []Violation) Content {
+	rows := make([][]string, len(violations))
+	for i, v := range violations {
+		row := ""
+		if v.Row > 0 {
+			row = strconv.Itoa(v.Row)
+		}
+		rows[i] = []string{row, v.Column, v.Value, v.Message}
+	}
+
+	return Content{
+		header: []string{"row", "column", "value", "problem"},
+		rows:   rows,
+	}
+}
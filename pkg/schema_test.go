@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		in     string
+		want   []string
+	}{
+		{
+			name:   "valid",
+			schema: "columns:\n  - name: id\n    type: int\n  - name: price\n    type: float\n",
+			in:     "id,price\n1,1.5\n2,\n",
+		},
+		{
+			name:   "types and patterns",
+			schema: "columns:\n  - name: id\n    type: int\n  - name: code\n    pattern: ^[A-Z]+$\n",
+			in:     "id,code\n1,AB\nx,cd\n",
+			want:   []string{`row 2, column "id": not of type int`, `row 2, column "code": does not match "^[A-Z]+$"`},
+		},
+		{
+			name:   "required",
+			schema: "columns:\n  - name: id\n    required: true\n  - name: name\n    required: true\n  - name: note\n",
+			in:     "id\n1\n<nil>\n\n",
+			want:   []string{`column "name": missing column`},
+		},
+		{
+			name:   "not nullable",
+			schema: "columns:\n  - name: id\n    nullable: false\n  - name: name\n    nullable: false\n  - name: note\n    nullable: true\n",
+			in:     "id,note\n1,\n<nil>,\n,<nil>\n",
+			want:   []string{`row 2, column "id": missing value`, `row 3, column "id": missing value`},
+		},
+		{
+			name:   "json schema",
+			schema: `{"properties": {"id": {"type": "integer", "nullable": false}, "name": {"type": ["string", "null"]}}, "required": ["id", "name"]}`,
+			in:     "id,name\n1,\nx,ann\n,ann\n",
+			want:   []string{`row 2, column "id": not of type int`, `row 3, column "id": missing value`},
+		},
+		{
+			name:   "strict",
+			schema: "strict: true\ncolumns:\n  - name: id\n",
+			in:     "id,extra\n1,2\n",
+			want:   []string{`column "extra": undeclared column`},
+		},
+		{
+			name:   "strict with other case",
+			schema: "strict: true\ncolumns:\n  - name: Price\n    type: float\n",
+			in:     "price\n1.5\nfree\n",
+			want:   []string{`row 2, column "Price": not of type float`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSchema(strings.NewReader(tt.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := (&CSVParser{}).Parse(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			violations, err := Validate(c, s, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
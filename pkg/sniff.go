@@ -0,0 +1,203 @@
+package pkg
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// CSVOptions describes the dialect of a CSV document.
+type CSVOptions struct {
+	// Delimiter is the field delimiter.
+	Delimiter rune
+	// Quote is the character used to quote fields.
+	Quote rune
+	// Header reports whether the first record is a header.
+	Header bool
+}
+
+// Parser returns a CSVParser for the dialect.
+func (o CSVOptions) Parser() *CSVParser {
+	return &CSVParser{
+		Delimiter: o.Delimiter,
+		Quote:     o.Quote,
+		NoHeader:  !o.Header,
+	}
+}
+
+var csvDelimiters = []rune{',', '\t', ';', '|'}
+
+// SniffCSV guesses the dialect of a CSV document from its first lines.
+// It reads at most a few kilobytes, but as the bytes are consumed the
+// caller typically passes a sample of the document, e.g. obtained with
+// bufio.Reader.Peek.
+func SniffCSV(r io.Reader) (CSVOptions, error) {
+	sample, err := io.ReadAll(io.LimitReader(r, sniffLen+1))
+	if err != nil {
+		return CSVOptions{}, err
+	}
+
+	complete := len(sample) <= sniffLen
+	if !complete {
+		sample = sample[:sniffLen]
+	}
+
+	return sniffCSV(sniffLines(string(sample), complete)), nil
+}
+
+func sniffCSV(lines []string) CSVOptions {
+	quote := sniffQuote(lines)
+	delimiter := sniffDelimiter(lines, quote)
+
+	return CSVOptions{
+		Delimiter: delimiter,
+		Quote:     quote,
+		Header:    sniffHeader(lines, delimiter, quote),
+	}
+}
+
+// singleQuoted matches a field quoted with single quotes.
+var singleQuoted = regexp.MustCompile(`(^|[,\t;|])'[^']*'($|[,\t;|])`)
+
+// sniffQuote returns a single quote if fields are quoted with it and no
+// field is quoted with double quotes, a double quote otherwise.
+func sniffQuote(lines []string) rune {
+	sample := strings.Join(lines, "\n")
+	if !strings.Contains(sample, `"`) && singleQuoted.MatchString(sample) {
+		return '\''
+	}
+
+	return '"'
+}
+
+// sniffDelimiter picks the candidate delimiter which occurs most often
+// in the first line and equally often in all other sampled lines,
+// ignoring quoted sections.
+func sniffDelimiter(lines []string, quote rune) rune {
+	best, bestCount := ',', 0
+	if len(lines) == 0 {
+		return best
+	}
+
+	for _, candidate := range csvDelimiters {
+		count := countUnquoted(lines[0], candidate, quote)
+		if count == 0 {
+			continue
+		}
+
+		consistent := true
+		for _, line := range lines[1:] {
+			if countUnquoted(line, candidate, quote) != count {
+				consistent = false
+				break
+			}
+		}
+
+		if consistent && count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+
+	return best
+}
+
+func countUnquoted(line string, r, quote rune) int {
+	count := 0
+	quoted := false
+	for _, c := range line {
+		switch {
+		case c == quote:
+			quoted = !quoted
+		case c == r && !quoted:
+			count++
+		}
+	}
+
+	return count
+}
+
+// sniffHeader guesses whether the first line is a header, see
+// DetectHeader.
+func sniffHeader(lines []string, delimiter, quote rune) bool {
+	records := make([][]string, len(lines))
+	for i, line := range lines {
+		records[i] = splitUnquoted(line, delimiter, quote)
+	}
+
+	return DetectHeader(records)
+}
+
+// DetectHeader guesses whether the first of the records is a header,
+// e.g. a row of names above rows of numbers. Every column votes for a
+// header if its first value differs in type, see InferType, or in
+// length from the consistent values below it, and against a header if
+// it does not. A header is assumed unless the votes against it prevail,
+// as dropping a record is less harmful than losing the header.
+func DetectHeader(records [][]string) bool {
+	if len(records) < 2 {
+		return true
+	}
+
+	votes := 0
+	for col, first := range records[0] {
+		var rest []string
+		for _, record := range records[1:] {
+			if col < len(record) {
+				rest = append(rest, record[col])
+			}
+		}
+		if len(rest) == 0 || first == "" {
+			continue
+		}
+
+		if typ := InferType(rest); typ != TypeString {
+			firstType := InferType([]string{first})
+			if firstType == typ || firstType.Numeric() && typ.Numeric() {
+				votes--
+			} else {
+				votes++
+			}
+			continue
+		}
+
+		length := len(rest[0])
+		sameLength := true
+		for _, v := range rest[1:] {
+			if len(v) != length {
+				sameLength = false
+				break
+			}
+		}
+
+		if sameLength {
+			if len(first) == length {
+				votes--
+			} else {
+				votes++
+			}
+		}
+	}
+
+	return votes >= 0
+}
+
+// splitUnquoted splits the line at delimiters outside of quotes and
+// strips the quotes of the fields.
+func splitUnquoted(line string, delimiter, quote rune) []string {
+	var fields []string
+	var field strings.Builder
+	quoted := false
+	for _, c := range line {
+		switch {
+		case c == quote:
+			quoted = !quoted
+		case c == delimiter && !quoted:
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteRune(c)
+		}
+	}
+
+	return append(fields, field.String())
+}
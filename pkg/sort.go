@@ -0,0 +1,148 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type sortKind int
+
+const (
+	sortString sortKind = iota
+	sortNumber
+	sortTime
+)
+
+type sortKey struct {
+	index int
+	desc  bool
+	kind  sortKind
+}
+
+// SortBy returns a Transform which sorts the rows by one or more
+// columns, e.g. "age desc, name asc". Columns whose values are all
+// numbers or all dates are compared as such, all other columns are
+// compared as strings. Empty values sort first. The sort is stable.
+func SortBy(spec string) Transform {
+	return func(c Content) (Content, error) {
+		keys, err := parseSortSpec(spec, c.header, c.column)
+		if err != nil {
+			return Content{}, err
+		}
+
+		rows := make([][]string, len(c.rows))
+		copy(rows, c.rows)
+
+		sort.SliceStable(rows, func(i, j int) bool {
+			for _, key := range keys {
+				cmp := compareValues(cell(rows[i], key.index), cell(rows[j], key.index), key.kind)
+				if cmp == 0 {
+					continue
+				}
+				if key.desc {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+			return false
+		})
+
+		return Content{
+			header: c.header,
+			rows:   rows,
+		}, nil
+	}
+}
+
+// parseSortSpec resolves the columns of the spec in the header. The
+// kind of every column is detected from its values.
+func parseSortSpec(spec string, header []string, column func(int) []string) ([]sortKey, error) {
+	var keys []sortKey
+	for _, part := range strings.Split(spec, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+
+		key := sortKey{}
+		name := part
+		if last := strings.ToLower(fields[len(fields)-1]); len(fields) > 1 && (last == "asc" || last == "desc") {
+			key.desc = last == "desc"
+			name = strings.Join(fields[:len(fields)-1], " ")
+		}
+		name = strings.TrimSpace(name)
+
+		key.index = columnIndex(header, name)
+		if key.index < 0 {
+			return nil, fmt.Errorf("column %q does not exist", name)
+		}
+		key.kind = detectSortKind(column(key.index))
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no sort columns given")
+	}
+
+	return keys, nil
+}
+
+func detectSortKind(values []string) sortKind {
+	switch t := InferType(values); {
+	case t.Numeric():
+		return sortNumber
+	case t == TypeTime:
+		return sortTime
+	}
+
+	return sortString
+}
+
+// compareValues compares two values of a column of the given kind.
+func compareValues(a, b string, kind sortKind) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return -1
+	case b == "":
+		return 1
+	}
+
+	switch kind {
+	case sortNumber:
+		x, _ := parseNumber(a)
+		y, _ := parseNumber(b)
+		return compareFloats(x, y)
+	case sortTime:
+		x, _ := parseTime(a)
+		y, _ := parseTime(b)
+		return compareTimes(x, y)
+	}
+
+	return strings.Compare(a, b)
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+
+	return 0
+}
+
+// cell returns the value at index i of the row, or an empty string if
+// the row is too short.
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+
+	return ""
+}
@@ -0,0 +1,237 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// FormatSpilled converts the content of the reader to a single text
+// table using the supplied streaming parser. At most memRows rows are
+// held in memory, further rows are spilled to a temporary file, so that
+// inputs larger than the memory can be rendered. The column widths are
+// computed while reading, and the table is rendered in batches of
+// memRows rows afterwards.
+// Control characters are removed from the values like by the
+// TableRenderer. Of the options, only the transformations are used, and
+// they are applied to batches of memRows rows like by FormatStream.
+func FormatSpilled(p StreamParser, r io.Reader, w io.Writer, memRows int, opts ...Option) error {
+	if memRows < 1 {
+		memRows = 1
+	}
+	o := newOptions(opts)
+
+	s := &rowSpool{limit: memRows}
+	defer s.Close()
+
+	var header []string
+	var widths []int
+	grow := func(row []string) {
+		for i, value := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if width := streamWidth(value); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	// the rows read are transformed in batches before they are spooled
+	var input []string
+	var batch [][]string
+	spool := func() error {
+		h, rows, err := transformBatch(o, input, batch)
+		batch = batch[:0]
+		if err != nil {
+			return err
+		}
+
+		header = h
+		for _, row := range rows {
+			grow(row)
+			if err := s.add(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	onHeader := func(h []string) error {
+		input = sanitizeRow(h, SanitizeStrip)
+		return nil
+	}
+
+	onRow := func(row []string) error {
+		batch = append(batch, sanitizeRow(row, SanitizeStrip))
+		if len(batch) >= memRows {
+			return spool()
+		}
+		return nil
+	}
+
+	if err := p.ParseStream(r, onHeader, onRow); err != nil {
+		return err
+	}
+	// the last batch, which also transforms the header of empty inputs
+	if err := spool(); err != nil {
+		return err
+	}
+	header = headerTexts(header)
+	grow(header)
+
+	// a batch is rendered once the next one is read, so that the last
+	// one is known to draw the bottom border
+	var pending [][]string
+	first := true
+	flush := func(last bool) {
+		table := tablewriter.NewWriter(w)
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Right: true, Top: first, Bottom: last})
+		if first {
+			table.SetHeader(header)
+		}
+		for i, width := range widths {
+			table.SetColMinWidth(i, width)
+		}
+		table.AppendBulk(pending)
+		table.Render()
+
+		first = false
+	}
+
+	err := s.each(memRows, func(batch [][]string) error {
+		if pending != nil {
+			flush(false)
+		}
+		pending = batch
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	flush(true)
+
+	return nil
+}
+
+// streamWidth returns the width tablewriter gives the value, which it
+// wraps at MAX_ROW_WIDTH unless words are longer.
+func streamWidth(value string) int {
+	lines := strings.Split(value, "\n")
+
+	width := 0
+	for _, line := range lines {
+		if w := tablewriter.DisplayWidth(line); w > width {
+			width = w
+		}
+	}
+	if width > tablewriter.MAX_ROW_WIDTH {
+		width = tablewriter.MAX_ROW_WIDTH
+	}
+
+	wrapped := width
+	for _, line := range lines {
+		parts, _ := tablewriter.WrapString(line, width)
+		for _, part := range parts {
+			if w := tablewriter.DisplayWidth(part); w > wrapped {
+				wrapped = w
+			}
+		}
+	}
+
+	return wrapped
+}
+
+// rowSpool holds rows in memory up to its limit and writes further rows
+// to a temporary CSV file.
+type rowSpool struct {
+	limit int
+	rows  [][]string
+	file  *os.File
+	out   *csv.Writer
+}
+
+func (s *rowSpool) add(row []string) error {
+	if len(s.rows) < s.limit {
+		s.rows = append(s.rows, row)
+		return nil
+	}
+
+	if s.file == nil {
+		f, err := os.CreateTemp("", "table-spill-*.csv")
+		if err != nil {
+			return fmt.Errorf("create spill file: %w", err)
+		}
+		s.file, s.out = f, csv.NewWriter(f)
+	}
+
+	return s.out.Write(row)
+}
+
+// each calls fn with batches of at most n rows, in the order they were
+// added.
+func (s *rowSpool) each(n int, fn func([][]string) error) error {
+	for start := 0; start < len(s.rows); start += n {
+		end := start + n
+		if end > len(s.rows) {
+			end = len(s.rows)
+		}
+		if err := fn(s.rows[start:end]); err != nil {
+			return err
+		}
+	}
+
+	if s.file == nil {
+		return nil
+	}
+
+	s.out.Flush()
+	if err := s.out.Error(); err != nil {
+		return fmt.Errorf("write spill file: %w", err)
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	r := csv.NewReader(bufio.NewReader(s.file))
+	r.FieldsPerRecord = -1
+	batch := make([][]string, 0, n)
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read spill file: %w", err)
+		}
+
+		batch = append(batch, row)
+		if len(batch) == n {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([][]string, 0, n)
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+
+	return nil
+}
+
+// Close removes the spill file.
+func (s *rowSpool) Close() error {
+	if s.file == nil {
+		return nil
+	}
+
+	s.file.Close()
+	return os.Remove(s.file.Name())
+}
@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// sqlNull is the text of NULL values in query results.
+const sqlNull = "NULL"
+
+// QuerySQL runs the query on the database and converts the result set to
+// the Content representation. The columns are named as returned by the
+// database, NULL values are rendered as "NULL".
+func QuerySQL(db *sql.DB, query string, args ...interface{}) (Content, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return Content{}, err
+	}
+	defer rows.Close()
+
+	return FromSQLRows(rows)
+}
+
+// FromSQLRows reads the remaining rows of a result set and converts them
+// to the Content representation, so query results can be rendered with
+// FormatContent. Values are converted to text like sql.NullString does,
+// NULL values are rendered as "NULL". The caller remains responsible for
+// closing rows.
+func FromSQLRows(rows *sql.Rows) (Content, error) {
+	return FromSQLRowsNull(rows, sqlNull)
+}
+
+// FromSQLRowsNull is FromSQLRows, which renders NULL values as the given
+// text, e.g. "-", instead of "NULL".
+func FromSQLRowsNull(rows *sql.Rows, null string) (Content, error) {
+	header, err := rows.Columns()
+	if err != nil {
+		return Content{}, err
+	}
+
+	values := make([]sql.NullString, len(header))
+	dest := make([]interface{}, len(header))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	out := Content{header: header}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return Content{}, fmt.Errorf("row %d: %w", len(out.rows)+1, err)
+		}
+
+		row := make([]string, len(values))
+		for i, v := range values {
+			row[i] = null
+			if v.Valid {
+				row[i] = v.String
+			}
+		}
+		out.rows = append(out.rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return Content{}, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,178 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SQLDialect selects the identifier quoting, string escaping and column
+// types of the statements emitted by a SQLRenderer.
+type SQLDialect int
+
+const (
+	// SQLite quotes identifiers with double quotes.
+	SQLite SQLDialect = iota
+	// Postgres quotes identifiers with double quotes.
+	Postgres
+	// MySQL quotes identifiers with backticks and escapes backslashes.
+	MySQL
+)
+
+// ParseSQLDialect returns the dialect with the given name, i.e.
+// "sqlite", "postgres" or "mysql".
+func ParseSQLDialect(name string) (SQLDialect, error) {
+	switch strings.ToLower(name) {
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	case "postgres", "postgresql":
+		return Postgres, nil
+	case "mysql", "mariadb":
+		return MySQL, nil
+	}
+
+	return SQLite, fmt.Errorf("unknown sql dialect %q", name)
+}
+
+// sqlDefaultTable is the table name used if SQLRenderer.Table is unset.
+const sqlDefaultTable = "data"
+
+// SQLRenderer is a renderer implementation that emits a CREATE TABLE
+// statement followed by an INSERT statement per row. Columns holding
+// only integers or numbers get a numeric type, all others are text.
+// Null values and empty values of numeric columns are inserted as NULL.
+type SQLRenderer struct {
+	// Table is the name of the table, "data" is used if it is unset.
+	Table string
+	// Dialect selects the SQL dialect.
+	Dialect SQLDialect
+	// NoCreate omits the CREATE TABLE statement.
+	NoCreate bool
+	// Nulls are the texts of values which are not set, see
+	// TableRenderer.Nulls.
+	Nulls *NullStrings
+}
+
+type sqlColumnType int
+
+const (
+	sqlText sqlColumnType = iota
+	sqlInteger
+	sqlReal
+)
+
+// Render writes the Content as SQL statements to the writer.
+func (s *SQLRenderer) Render(c Content, w io.Writer) error {
+	table := s.Table
+	if table == "" {
+		table = sqlDefaultTable
+	}
+	table = s.identifier(table)
+
+	types := sqlColumnTypes(c, s.Nulls)
+
+	columns := make([]string, len(c.header))
+	for i, name := range c.header {
+		columns[i] = s.identifier(name)
+	}
+
+	bw := bufio.NewWriter(w)
+
+	if !s.NoCreate {
+		fmt.Fprintf(bw, "CREATE TABLE %s (\n", table)
+		for i, column := range columns {
+			separator := ","
+			if i == len(columns)-1 {
+				separator = ""
+			}
+			fmt.Fprintf(bw, "  %s %s%s\n", column, s.typeName(types[i]), separator)
+		}
+		bw.WriteString(");\n")
+	}
+
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES (", table, strings.Join(columns, ", "))
+	for _, row := range c.rows {
+		values := make([]string, len(c.header))
+		for i := range values {
+			values[i] = s.literal(cell(row, i), types[i])
+		}
+		bw.WriteString(prefix + strings.Join(values, ", ") + ");\n")
+	}
+
+	return bw.Flush()
+}
+
+// sqlColumnTypes returns the type of every column, numeric types are
+// only chosen for values which are valid SQL numeric literals. Null
+// values are ignored.
+func sqlColumnTypes(c Content, nulls *NullStrings) []sqlColumnType {
+	types := make([]sqlColumnType, len(c.header))
+	for i := range types {
+		kind, seen := sqlInteger, false
+		for _, row := range c.rows {
+			v := cell(row, i)
+			if v == "" || isNull(v, nulls) {
+				continue
+			}
+
+			seen = true
+			if !jsonNumber.MatchString(v) {
+				kind = sqlText
+				break
+			}
+			if strings.ContainsAny(v, ".eE") {
+				kind = sqlReal
+			}
+		}
+
+		if seen {
+			types[i] = kind
+		}
+	}
+
+	return types
+}
+
+func (s *SQLRenderer) typeName(t sqlColumnType) string {
+	switch {
+	case t == sqlInteger && s.Dialect == SQLite:
+		return "INTEGER"
+	case t == sqlInteger:
+		return "BIGINT"
+	case t == sqlReal && s.Dialect == SQLite:
+		return "REAL"
+	case t == sqlReal && s.Dialect == Postgres:
+		return "DOUBLE PRECISION"
+	case t == sqlReal:
+		return "DOUBLE"
+	}
+
+	return "TEXT"
+}
+
+func (s *SQLRenderer) identifier(name string) string {
+	if s.Dialect == MySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (s *SQLRenderer) literal(value string, t sqlColumnType) string {
+	if isNull(value, s.Nulls) {
+		return "NULL"
+	}
+	if t != sqlText {
+		if value == "" {
+			return "NULL"
+		}
+		return value
+	}
+
+	if s.Dialect == MySQL {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"context"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// RowFunc is invoked by a StreamParser for every record it reads.
+type RowFunc func(row []string) error
+
+// StreamParser describes an interface to parse a document row by row
+// without holding the whole document in memory.
+type StreamParser interface {
+	// ParseStream passes the header to onHeader and then calls onRow
+	// once for every row of the document.
+	ParseStream(r io.Reader, onHeader, onRow RowFunc) error
+}
+
+// FormatStream converts the content of the reader to a table format
+// using the supplied streaming parser. Rows are rendered as soon as
+// batchSize rows are read, so memory usage is bounded by the batch size
+// instead of the document size. Every batch is a separate table with
+// its own header and borders. Column widths only ever grow from one
+// batch to the next so consecutive batches line up as far as possible.
+// Control characters are handled like by the TableRenderer, see
+// WithSanitize. Of the other options, only the transformations are
+// used, see transformBatch. It is Format WithBatchSize(batchSize).
+func FormatStream(p StreamParser, r io.Reader, w io.Writer, batchSize int, opts ...Option) error {
+	return formatStream(context.Background(), p, r, w, batchSize, newOptions(opts))
+}
+
+// formatStream renders the rows of the stream parser in tables of at
+// most batchSize rows, see FormatStream.
+func formatStream(ctx context.Context, p StreamParser, r io.Reader, w io.Writer, batchSize int, o *options) error {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	rowCount := 0
+
+	var header []string
+	var batch [][]string
+	widths := map[int]int{}
+
+	grow := func(row []string) {
+		for i, value := range row {
+			width := tablewriter.DisplayWidth(value)
+			if width > tablewriter.MAX_ROW_WIDTH {
+				width = tablewriter.MAX_ROW_WIDTH
+			}
+			if width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		h, rows, err := transformBatch(o, header, batch)
+		batch = batch[:0]
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+
+		h = headerTexts(h)
+		table := tablewriter.NewWriter(w)
+		table.SetAutoFormatHeaders(false)
+		table.SetHeader(h)
+		grow(h)
+		for _, row := range rows {
+			grow(row)
+		}
+		for i, width := range widths {
+			table.SetColMinWidth(i, width)
+		}
+		table.AppendBulk(rows)
+		table.Render()
+
+		return nil
+	}
+
+	onHeader := func(h []string) error {
+		header = sanitizeRow(h, o.sanitize)
+		return nil
+	}
+
+	onRow := func(row []string) error {
+		batch = append(batch, sanitizeRow(row, o.sanitize))
+		rowCount++
+		if err := canceled(ctx, rowCount); err != nil {
+			return err
+		}
+		if len(batch) >= batchSize {
+			return flush()
+		}
+		return nil
+	}
+
+	if err := p.ParseStream(r, onHeader, onRow); err != nil {
+		return err
+	}
+
+	return flush()
+}
+
+// transformBatch applies the transformations of the options to a batch
+// of rows, returning the transformed header and rows. Every batch is
+// transformed on its own, so only transformations of single rows, like
+// Filter, Replace, Mask, ComputeColumn and SelectColumns, give the same
+// result as for the whole input.
+func transformBatch(o *options, header []string, rows [][]string) ([]string, [][]string, error) {
+	c := Content{header: header, rows: rows}
+	var err error
+	for _, t := range o.allTransforms() {
+		if c, err = t(c); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return c.header, c.rows, nil
+}
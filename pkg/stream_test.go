@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+const streamInput = "name,price,card\napple,50,1234\npear,150,5678\nkiwi,300,9012\n"
+
+func TestFormatStreamTransforms(t *testing.T) {
+	formats := map[string]func(io.Reader, io.Writer, ...Option) error{
+		"FormatStream": func(r io.Reader, w io.Writer, opts ...Option) error {
+			return FormatStream(&CSVParser{}, r, w, 2, opts...)
+		},
+		"FormatSpilled": func(r io.Reader, w io.Writer, opts ...Option) error {
+			return FormatSpilled(&CSVParser{}, r, w, 2, opts...)
+		},
+	}
+
+	opts := []Option{
+		WithFilter("price > 100"),
+		WithMask(map[string]MaskMode{"card": MaskFull}),
+		WithTransform(SelectColumns([]string{"name", "card"})),
+	}
+
+	for name, format := range formats {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := format(strings.NewReader(streamInput), &out, opts...); err != nil {
+				t.Fatal(err)
+			}
+
+			got := out.String()
+			for _, want := range []string{"NAME", "CARD", "pear", "kiwi", "****"} {
+				if !strings.Contains(got, want) {
+					t.Errorf("output lacks %q:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range []string{"PRICE", "apple", "5678"} {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("output holds %q:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatStreamSanitize(t *testing.T) {
+	in := "name,note\napple,\"a\x1b[31mred\"\n"
+
+	tests := map[Sanitize]string{
+		SanitizeStrip:  "ared",
+		SanitizeEscape: `a\x1b[31mred`,
+		SanitizeNone:   "a\x1b[31mred",
+	}
+
+	for mode, want := range tests {
+		var out bytes.Buffer
+		if err := FormatStream(&CSVParser{}, strings.NewReader(in), &out, 2, WithSanitize(mode)); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("mode %d: output lacks %q:\n%s", mode, want, out.String())
+		}
+	}
+}
+
+func TestFormatWithBatchSize(t *testing.T) {
+	var stream, batched bytes.Buffer
+	if err := FormatStream(&CSVParser{}, strings.NewReader(streamInput), &stream, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := Format(&CSVParser{}, strings.NewReader(streamInput), &batched, WithBatchSize(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if stream.String() != batched.String() {
+		t.Errorf("got\n%s\nwant\n%s", batched.String(), stream.String())
+	}
+	// every batch is a table of its own
+	if n := strings.Count(batched.String(), "NAME"); n != 2 {
+		t.Errorf("got %d headers, want 2:\n%s", n, batched.String())
+	}
+}
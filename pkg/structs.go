@@ -0,0 +1,109 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FromStructs converts a slice of structs, or pointers to structs, to the
+// Content representation. Every exported field is a column named like the
+// field, unless the field has a `table:"name"` tag. Fields tagged with
+// `table:"-"` are skipped and the fields of embedded structs are promoted
+// like encoding/json does. Values are formatted with fmt.Sprint, so types
+// implementing fmt.Stringer or error are printed accordingly.
+func FromStructs[T any](items []T) (Content, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return Content{}, fmt.Errorf("%s is not a struct type", typ)
+	}
+
+	fields := structFields(typ, nil)
+
+	out := Content{header: make([]string, len(fields))}
+	for i, f := range fields {
+		out.header[i] = f.name
+	}
+
+	for _, item := range items {
+		v := reflect.ValueOf(&item).Elem()
+		if v.Kind() == reflect.Pointer {
+			v = v.Elem()
+		}
+
+		row := make([]string, len(fields))
+		for i, f := range fields {
+			row[i] = structValue(v, f.index)
+		}
+		out.rows = append(out.rows, row)
+	}
+
+	return out, nil
+}
+
+type structField struct {
+	name  string
+	index []int
+}
+
+// structFields returns the columns of a struct type in field order.
+func structFields(typ reflect.Type, index []int) []structField {
+	var fields []structField
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("table")
+		if tag == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && tag == "" && ft.Kind() == reflect.Struct {
+			fields = append(fields, structFields(ft, fieldIndex)...)
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Name
+		if tag != "" {
+			name = tag
+		}
+		fields = append(fields, structField{name: name, index: fieldIndex})
+	}
+
+	return fields
+}
+
+// structValue formats the field at the index path, nil pointers to
+// embedded structs yield an empty value.
+func structValue(v reflect.Value, index []int) string {
+	if !v.IsValid() {
+		return ""
+	}
+
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return ""
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	if !v.CanInterface() {
+		// promoted through an unexported embedded struct
+		return fmt.Sprint(v)
+	}
+
+	return fmt.Sprint(v.Interface())
+}
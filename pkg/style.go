@@ -0,0 +1,189 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rivo/uniseg"
+)
+
+// Style selects the borders drawn by the TableRenderer.
+type Style int
+
+const (
+	// StyleASCII draws borders with +, - and |.
+	StyleASCII Style = iota
+	// StyleLight draws borders with light box-drawing characters.
+	StyleLight
+	// StyleHeavy draws borders with heavy box-drawing characters.
+	StyleHeavy
+	// StyleDouble draws borders with double box-drawing characters.
+	StyleDouble
+	// StyleRounded draws borders with light box-drawing characters and
+	// rounded corners.
+	StyleRounded
+	// StyleMinimal draws no borders but a line below the header.
+	StyleMinimal
+)
+
+var styleNames = []string{"ascii", "light", "heavy", "double", "rounded", "minimal"}
+
+// ParseStyle returns the style with the given name, i.e. "ascii",
+// "light", "heavy", "double", "rounded" or "minimal".
+func ParseStyle(name string) (Style, error) {
+	for i, styleName := range styleNames {
+		if strings.EqualFold(name, styleName) {
+			return Style(i), nil
+		}
+	}
+
+	return StyleASCII, fmt.Errorf("unknown style %q, supported styles: %s", name, strings.Join(styleNames, ", "))
+}
+
+func (s Style) String() string {
+	if s < 0 || int(s) >= len(styleNames) {
+		return fmt.Sprintf("Style(%d)", int(s))
+	}
+
+	return styleNames[s]
+}
+
+// boxChars are the characters of a box-drawing style. The corners and
+// junctions are indexed by row (top, middle, bottom) and column (left,
+// center, right).
+type boxChars struct {
+	horizontal rune
+	vertical   rune
+	junctions  [3][3]rune
+}
+
+var boxStyles = map[Style]boxChars{
+	StyleLight: {'─', '│', [3][3]rune{
+		{'┌', '┬', '┐'},
+		{'├', '┼', '┤'},
+		{'└', '┴', '┘'},
+	}},
+	StyleHeavy: {'━', '┃', [3][3]rune{
+		{'┏', '┳', '┓'},
+		{'┣', '╋', '┫'},
+		{'┗', '┻', '┛'},
+	}},
+	StyleDouble: {'═', '║', [3][3]rune{
+		{'╔', '╦', '╗'},
+		{'╠', '╬', '╣'},
+		{'╚', '╩', '╝'},
+	}},
+	StyleRounded: {'─', '│', [3][3]rune{
+		{'╭', '┬', '╮'},
+		{'├', '┼', '┤'},
+		{'╰', '┴', '╯'},
+	}},
+}
+
+// applyStyle configures the separators of the table for styles which
+// tablewriter supports directly.
+func applyStyle(table *tablewriter.Table, s Style) {
+	if s == StyleMinimal {
+		table.SetBorder(false)
+		table.SetCenterSeparator(" ")
+		table.SetColumnSeparator(" ")
+		table.SetRowSeparator("-")
+	}
+}
+
+// restyle replaces the ASCII borders drawn by tablewriter with the
+// box-drawing characters of the style. The positions of the column
+// separators are taken from the top border, so that separator
+// characters inside cell values are kept.
+func restyle(table string, s Style) string {
+	if s == StyleMinimal {
+		lines := strings.Split(table, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight(line, " ")
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	box, ok := boxStyles[s]
+	if !ok {
+		return table
+	}
+
+	lines := strings.Split(strings.TrimSuffix(table, "\n"), "\n")
+
+	var junctions map[int]bool
+	lastBorder := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "+") {
+			if junctions == nil {
+				junctions = map[int]bool{}
+				for col, r := range line {
+					if r == '+' {
+						junctions[col] = true
+					}
+				}
+			}
+			lastBorder = i
+		}
+	}
+	if junctions == nil {
+		return table
+	}
+
+	var b strings.Builder
+	for i, line := range lines {
+		border := strings.HasPrefix(line, "+")
+		row := 1
+		switch {
+		case border && i == 0:
+			row = 0
+		case border && i == lastBorder:
+			row = 2
+		}
+
+		// the columns of borders are counted per grapheme cluster, as
+		// tablewriter pads the cells
+		g := uniseg.NewGraphemes(line)
+		col, escape := 0, false
+		for g.Next() {
+			cluster := g.Str()
+			r := g.Runes()[0]
+			start, end := g.Positions()
+			width := graphemeWidth(cluster)
+			switch {
+			case r == '\x1b':
+				// ANSI escape sequences of colored values take no space
+				escape, width = true, 0
+			case escape:
+				escape, width = !unicode.IsLetter(r), 0
+			}
+
+			replaced := true
+			switch {
+			case border && r == '+' && start == 0:
+				r = box.junctions[row][0]
+			case border && r == '+' && end == len(line):
+				r = box.junctions[row][2]
+			case border && r == '+':
+				r = box.junctions[row][1]
+			case border && r == '-':
+				r = box.horizontal
+			case !border && r == '|' && junctions[col]:
+				r = box.vertical
+			default:
+				replaced = false
+			}
+			if replaced {
+				b.WriteRune(r)
+			} else {
+				b.WriteString(cluster)
+			}
+			col += width
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
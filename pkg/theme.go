@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/term"
+)
+
+// Theme colors the values of a TableRenderer using ANSI escape codes.
+// Every field holds SGR parameters, e.g. []int{1, 36} for bold cyan, nil
+// leaves the values uncolored.
+type Theme struct {
+	// Header colors the header.
+	Header []int
+	// Number colors the values of numeric columns, see Content.Types.
+	Number []int
+	// Negative colors negative numbers, in place of Number.
+	Negative []int
+	// Null colors missing values, i.e. "<nil>" and "NULL" unless the
+	// TableRenderer sets other Nulls.
+	Null []int
+	// AlternateRow is added to the colors of every second row, e.g. a
+	// background color to shade the rows.
+	AlternateRow []int
+}
+
+var (
+	// ThemeDefault colors the header, numbers, negative numbers and
+	// missing values.
+	ThemeDefault = &Theme{
+		Header:   []int{tablewriter.Bold, tablewriter.FgCyanColor},
+		Number:   []int{tablewriter.FgYellowColor},
+		Negative: []int{tablewriter.FgRedColor},
+		Null:     []int{tablewriter.FgHiBlackColor},
+	}
+	// ThemeZebra is ThemeDefault with alternating row shading.
+	ThemeZebra = &Theme{
+		Header:       ThemeDefault.Header,
+		Number:       ThemeDefault.Number,
+		Negative:     ThemeDefault.Negative,
+		Null:         ThemeDefault.Null,
+		AlternateRow: []int{48, 5, 236},
+	}
+)
+
+var themes = map[string]*Theme{
+	"none":    nil,
+	"default": ThemeDefault,
+	"zebra":   ThemeZebra,
+}
+
+// ParseTheme returns the theme with the given name, i.e. "default",
+// "zebra" or "none", which returns nil.
+func ParseTheme(name string) (*Theme, error) {
+	theme, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q, supported themes: default, zebra, none", name)
+	}
+
+	return theme, nil
+}
+
+// ColorEnabled reports whether colored output should be written to w,
+// i.e. whether w is a terminal and the NO_COLOR environment variable is
+// unset, see https://no-color.org.
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	f, ok := w.(fileDescriptor)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// fileDescriptor is implemented by files, e.g. *os.File, and by types
+// embedding them.
+type fileDescriptor interface {
+	Fd() uintptr
+}
+
+// headerColors returns the colors of the header columns.
+func (t *Theme) headerColors(columns int) []tablewriter.Colors {
+	colors := make([]tablewriter.Colors, columns)
+	for i := range colors {
+		colors[i] = t.Header
+	}
+
+	return colors
+}
+
+// rowColors returns the colors of the values of the i-th row, whose
+// columns have the given types. Values are null as reported by isNull.
+func (t *Theme) rowColors(i int, row []string, types []ColumnType, nulls *NullStrings) []tablewriter.Colors {
+	colors := make([]tablewriter.Colors, len(row))
+	for j, value := range row {
+		n, ok := parseNumber(value)
+		ok = ok && j < len(types) && types[j].Numeric()
+
+		var color []int
+		switch {
+		case isNull(value, nulls):
+			color = t.Null
+		case ok && n < 0 && t.Negative != nil:
+			color = t.Negative
+		case ok:
+			color = t.Number
+		}
+
+		if i%2 == 1 {
+			color = append(append([]int(nil), color...), t.AlternateRow...)
+		}
+		colors[j] = color
+	}
+
+	return colors
+}
@@ -0,0 +1,136 @@
+package pkg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeFormats are the names of layouts FormatTimes accepts in place of
+// a Go layout. "unix" and "unixmilli" print epoch seconds and
+// milliseconds.
+var timeFormats = map[string]string{
+	"rfc3339":   time.RFC3339,
+	"rfc1123":   time.RFC1123,
+	"datetime":  time.DateTime,
+	"date":      time.DateOnly,
+	"time":      time.TimeOnly,
+	"kitchen":   time.Kitchen,
+	"unix":      "unix",
+	"unixmilli": "unixmilli",
+}
+
+// FormatTimes returns a Transform which reformats the dates and times
+// of the named columns, or of all columns inferred to hold dates and
+// times if none are named. The layout is a Go time layout, e.g.
+// "02.01.2006 15:04", or one of "rfc3339" (the default), "rfc1123",
+// "datetime", "date", "time", "kitchen", "unix" and "unixmilli". Times
+// are converted to the location if it is set, values without time
+// zone are taken to be in it. Besides the layouts of dates and times
+// the type inference recognizes, the named columns may hold epoch
+// seconds, milliseconds, microseconds or nanoseconds, which are told
+// apart by their magnitude. Values which cannot be parsed are kept as
+// they are. It fails if a column does not exist.
+func FormatTimes(columns []string, layout string, loc *time.Location) Transform {
+	return func(c Content) (Content, error) {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		if named, ok := timeFormats[strings.ToLower(layout)]; ok {
+			layout = named
+		}
+		zone := loc
+		if zone == nil {
+			zone = time.UTC
+		}
+
+		formatted := make([]bool, len(c.header))
+		for _, name := range columns {
+			idx := c.columnIndex(name)
+			if idx < 0 {
+				return Content{}, fmt.Errorf("column %q does not exist", name)
+			}
+			formatted[idx] = true
+		}
+		if len(columns) == 0 {
+			for j, t := range c.Types() {
+				formatted[j] = t == TypeTime
+			}
+		}
+
+		rows := make([][]string, len(c.rows))
+		for i, row := range c.rows {
+			rows[i] = append([]string(nil), row...)
+			for j, value := range rows[i] {
+				if j >= len(formatted) || !formatted[j] {
+					continue
+				}
+
+				t, ok := parseTimeIn(value, zone)
+				if !ok {
+					t, ok = parseEpoch(value)
+				}
+				if !ok {
+					continue
+				}
+				if loc != nil {
+					t = t.In(loc)
+				}
+				rows[i][j] = formatTime(t, layout)
+			}
+		}
+
+		return Content{
+			header: c.header,
+			rows:   rows,
+		}, nil
+	}
+}
+
+// parseEpoch parses a number of seconds, milliseconds, microseconds or
+// nanoseconds since 1970. Seconds may have a fraction, the others are
+// integers.
+func parseEpoch(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || strings.ContainsAny(s, "eE") || math.IsInf(n, 0) || math.IsNaN(n) {
+		return time.Time{}, false
+	}
+
+	switch abs := math.Abs(n); {
+	case abs < 1e11:
+		sec, frac := math.Modf(n)
+		return time.Unix(int64(sec), int64(math.Round(frac*1e9))).UTC(), true
+	case strings.Contains(s, "."):
+		return time.Time{}, false
+	}
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch abs := math.Abs(n); {
+	case abs < 1e14:
+		return time.UnixMilli(i).UTC(), true
+	case abs < 1e17:
+		return time.UnixMicro(i).UTC(), true
+	}
+
+	return time.Unix(0, i).UTC(), true
+}
+
+// formatTime formats the time with the layout, or as epoch seconds or
+// milliseconds.
+func formatTime(t time.Time, layout string) string {
+	switch layout {
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "unixmilli":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+
+	return t.Format(layout)
+}
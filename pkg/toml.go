@@ -0,0 +1,111 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// TOMLParser is a parser implementation that parses TOML documents. The
+// tables of an array of tables, e.g. [[servers]], become the rows.
+type TOMLParser struct {
+	// Table is the dotted key of the array of tables holding the rows,
+	// e.g. "servers" or "inventory.hosts". The first array of tables of
+	// the document is used if it is unset.
+	Table string
+	// Flatten turns nested tables and arrays into separate columns using
+	// dot-notation, e.g. "owner.name" or "ports.0".
+	Flatten bool
+	// MaxDepth limits how many levels are flattened, zero flattens all
+	// levels.
+	MaxDepth int
+	// Nested selects how arrays and tables which are not flattened
+	// are printed, as compact JSON by default.
+	Nested NestedMode
+	// Nulls are the texts of missing keys,
+	// DefaultNullStrings is used if it is unset.
+	Nulls *NullStrings
+}
+
+// Parse converts the content of a reader to the Content representation.
+func (t *TOMLParser) Parse(reader io.Reader) (Content, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return Content{}, err
+	}
+
+	var doc map[string]interface{}
+	md, err := toml.Decode(string(data), &doc)
+	if err != nil {
+		return Content{}, tomlError(data, err)
+	}
+
+	key := t.Table
+	if key == "" {
+		for _, k := range md.Keys() {
+			if md.Type(k...) == "ArrayHash" {
+				key = k.String()
+				break
+			}
+		}
+		if key == "" {
+			return Content{}, fmt.Errorf("document has no array of tables")
+		}
+	}
+
+	var value interface{} = doc
+	for _, part := range strings.Split(key, ".") {
+		table, ok := value.(map[string]interface{})
+		if !ok {
+			return Content{}, fmt.Errorf("%q is not an array of tables", key)
+		}
+		value = table[part]
+	}
+
+	var rows []map[string]interface{}
+	switch v := value.(type) {
+	case []map[string]interface{}:
+		rows = v
+	case []interface{}:
+		for i, element := range v {
+			row, ok := element.(map[string]interface{})
+			if !ok {
+				return Content{}, fmt.Errorf("%s: element %d is not a table", key, i+1)
+			}
+			rows = append(rows, row)
+		}
+	case nil:
+		return Content{}, fmt.Errorf("%q not found", key)
+	default:
+		return Content{}, fmt.Errorf("%q is not an array of tables", key)
+	}
+
+	if t.Flatten {
+		rows = flattenRows(rows, t.MaxDepth)
+	}
+
+	return mapsToContent(rows, t.Nulls, t.Nested), nil
+}
+
+// tomlErrorPrefix matches the position prefix of toml.ParseError messages.
+var tomlErrorPrefix = regexp.MustCompile(`^toml: line \d+( \(last key "[^"]*"\))?: `)
+
+// tomlError converts errors of the TOML decoder into a ParseError. The
+// line is taken from the offset, as the decoder counts a trailing
+// newline as part of the next line.
+func tomlError(data []byte, err error) error {
+	var perr toml.ParseError
+	if errors.As(err, &perr) {
+		msg := tomlErrorPrefix.ReplaceAllString(perr.Error(), "")
+		if perr.LastKey != "" {
+			msg += fmt.Sprintf(" (last key %q)", perr.LastKey)
+		}
+		return offsetError(data, int64(perr.Position.Start), fmt.Errorf("toml: %s", msg))
+	}
+
+	return err
+}
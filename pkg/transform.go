@@ -0,0 +1,258 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// NumberRows returns a copy of the Content with a leading "#" column
+// holding the 1-based row number.
+func NumberRows(c Content) Content {
+	header := append([]string{"#"}, c.header...)
+
+	rows := make([][]string, len(c.rows))
+	for i, row := range c.rows {
+		rows[i] = append([]string{strconv.Itoa(i + 1)}, row...)
+	}
+
+	return Content{
+		header: header,
+		rows:   rows,
+	}
+}
+
+// SelectColumns returns a Transform which keeps only the named columns,
+// in the given order. It fails if a column does not exist.
+func SelectColumns(columns []string) Transform {
+	return func(c Content) (Content, error) {
+		indices := make([]int, len(columns))
+		for i, name := range columns {
+			indices[i] = c.columnIndex(name)
+			if indices[i] < 0 {
+				return Content{}, fmt.Errorf("column %q does not exist", name)
+			}
+		}
+
+		header := make([]string, len(indices))
+		for i, idx := range indices {
+			header[i] = c.header[idx]
+		}
+
+		rows := make([][]string, len(c.rows))
+		for i := range c.rows {
+			row := make([]string, len(indices))
+			for j, idx := range indices {
+				row[j] = c.At(i, idx)
+			}
+			rows[i] = row
+		}
+
+		return Content{
+			header: header,
+			rows:   rows,
+		}, nil
+	}
+}
+
+// Filter returns a Transform which keeps only the rows for which the
+// expression evaluates to true, e.g. "status == 'error' && latency > 200".
+// See expr.go for the supported syntax.
+func Filter(expression string) Transform {
+	return func(c Content) (Content, error) {
+		e, err := compileExpr(expression, c)
+		if err != nil {
+			return Content{}, fmt.Errorf("invalid filter: %w", err)
+		}
+
+		var rows [][]string
+		for i, row := range c.rows {
+			if err := c.canceled(i); err != nil {
+				return Content{}, err
+			}
+			v, err := e.eval(row)
+			if err != nil {
+				return Content{}, fmt.Errorf("filter row %d: %w", i+1, err)
+			}
+
+			if v.truthy() {
+				rows = append(rows, row)
+			}
+		}
+
+		return Content{
+			header: c.header,
+			rows:   rows,
+		}, nil
+	}
+}
+
+// Replace returns a Transform which replaces the matches of the regular
+// expression in the values of the named columns, or of all columns if
+// none are named, e.g. Replace([]string{"email"}, "@.*", "@redacted").
+// The replacement may refer to submatches like regexp.ReplaceAllString,
+// e.g. "$1". It fails if the expression is invalid or a column does not
+// exist.
+func Replace(columns []string, pattern, replacement string) Transform {
+	return func(c Content) (Content, error) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Content{}, fmt.Errorf("invalid replace pattern: %w", err)
+		}
+
+		replaced := make([]bool, len(c.header))
+		for _, name := range columns {
+			idx := c.columnIndex(name)
+			if idx < 0 {
+				return Content{}, fmt.Errorf("column %q does not exist", name)
+			}
+			replaced[idx] = true
+		}
+		if len(columns) == 0 {
+			for j := range replaced {
+				replaced[j] = true
+			}
+		}
+
+		rows := make([][]string, len(c.rows))
+		for i, row := range c.rows {
+			if err := c.canceled(i); err != nil {
+				return Content{}, err
+			}
+			rows[i] = append([]string(nil), row...)
+			for j, value := range rows[i] {
+				if j < len(replaced) && replaced[j] {
+					rows[i][j] = re.ReplaceAllString(value, replacement)
+				}
+			}
+		}
+
+		return Content{
+			header: c.header,
+			rows:   rows,
+		}, nil
+	}
+}
+
+// ComputeColumn returns a Transform which sets the named column to the
+// value of the expression for every row, e.g. "price * qty". The column
+// is appended unless a column of that name exists, whose values are
+// replaced then. Values are converted to numbers for arithmetic, see
+// expr.go for the supported syntax. Rows for which the expression
+// cannot be evaluated, e.g. because a value is not a number, are left
+// empty.
+func ComputeColumn(name, expression string) Transform {
+	return func(c Content) (Content, error) {
+		e, err := compileExpr(expression, c)
+		if err != nil {
+			return Content{}, fmt.Errorf("invalid expression of column %q: %w", name, err)
+		}
+
+		header := c.header
+		idx := c.columnIndex(name)
+		if idx < 0 {
+			header = append(header[:len(header):len(header)], name)
+			idx = len(c.header)
+		}
+
+		rows := make([][]string, len(c.rows))
+		for i, row := range c.rows {
+			if err := c.canceled(i); err != nil {
+				return Content{}, err
+			}
+			computed := make([]string, len(header))
+			copy(computed, row)
+			computed[idx] = ""
+			if v, err := e.eval(row); err == nil {
+				computed[idx] = computedString(v)
+			}
+			rows[i] = computed
+		}
+
+		return Content{
+			header: header,
+			rows:   rows,
+		}, nil
+	}
+}
+
+// computedString formats the result of an expression. Numbers are
+// rounded to 12 significant digits, so that e.g. 0.1 * 3 is printed as
+// 0.3 rather than 0.30000000000000004.
+func computedString(v value) string {
+	if v.kind == kindNumber {
+		n, _ := strconv.ParseFloat(strconv.FormatFloat(v.n, 'g', 12, 64), 64)
+		return formatNumber(n)
+	}
+
+	return v.String()
+}
+
+// Transpose returns a copy of the Content with rows and columns swapped.
+// The first column holds the former header, the following columns are
+// named after the 1-based number of the former row.
+func Transpose(c Content) Content {
+	header := make([]string, len(c.rows)+1)
+	header[0] = "column"
+	for i := range c.rows {
+		header[i+1] = strconv.Itoa(i + 1)
+	}
+
+	rows := make([][]string, len(c.header))
+	for j, name := range c.header {
+		row := make([]string, len(c.rows)+1)
+		row[0] = name
+		for i := range c.rows {
+			row[i+1] = c.At(i, j)
+		}
+		rows[j] = row
+	}
+
+	return Content{
+		header: header,
+		rows:   rows,
+	}
+}
+
+// Limit returns a Transform which keeps at most the first n rows.
+func Limit(n int) Transform {
+	return func(c Content) (Content, error) {
+		if n < 0 {
+			return Content{}, fmt.Errorf("limit must not be negative")
+		}
+		if n < len(c.rows) {
+			c.rows = c.rows[:n]
+		}
+
+		return c, nil
+	}
+}
+
+// Offset returns a Transform which skips the first n rows.
+func Offset(n int) Transform {
+	return func(c Content) (Content, error) {
+		if n < 0 {
+			return Content{}, fmt.Errorf("offset must not be negative")
+		}
+		if n > len(c.rows) {
+			n = len(c.rows)
+		}
+		c.rows = c.rows[n:]
+
+		return c, nil
+	}
+}
+
+// Tail returns a Transform which keeps at most the last n rows.
+func Tail(n int) Transform {
+	return func(c Content) (Content, error) {
+		if n < 0 {
+			return Content{}, fmt.Errorf("tail must not be negative")
+		}
+		if n < len(c.rows) {
+			c.rows = c.rows[len(c.rows)-n:]
+		}
+
+		return c, nil
+	}
+}
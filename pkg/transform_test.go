@@ -0,0 +1,124 @@
+package pkg
+
+import "testing"
+
+const ordersCSV = "id,customer,price,qty\n" +
+	"1,alice,10,2\n" +
+	"2,bob,25.5,1\n" +
+	"3,alice,7,4\n" +
+	"4,carol,100,1\n" +
+	"5,bob,,3\n"
+
+func TestTransforms(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Transform
+		want      string
+	}{
+		{
+			name:      "select columns",
+			transform: SelectColumns([]string{"qty", "ID"}),
+			want:      "qty,id\n2,1\n1,2\n4,3\n1,4\n3,5\n",
+		},
+		{
+			name:      "filter numbers",
+			transform: Filter("price > 9"),
+			want:      "id,customer,price,qty\n1,alice,10,2\n2,bob,25.5,1\n4,carol,100,1\n",
+		},
+		{
+			name:      "filter strings",
+			transform: Filter(`customer == "bob" && qty > 1`),
+			want:      "id,customer,price,qty\n5,bob,,3\n",
+		},
+		{
+			name:      "sort numbers descending",
+			transform: SortBy("price desc"),
+			want:      "id,customer,price,qty\n4,carol,100,1\n2,bob,25.5,1\n1,alice,10,2\n3,alice,7,4\n5,bob,,3\n",
+		},
+		{
+			name:      "sort by several columns",
+			transform: SortBy("customer, qty desc"),
+			want:      "id,customer,price,qty\n3,alice,7,4\n1,alice,10,2\n5,bob,,3\n2,bob,25.5,1\n4,carol,100,1\n",
+		},
+		{
+			name:      "computed column",
+			transform: ComputeColumn("total", "price * qty"),
+			want:      "id,customer,price,qty,total\n1,alice,10,2,20\n2,bob,25.5,1,25.5\n3,alice,7,4,28\n4,carol,100,1,100\n5,bob,,3,\n",
+		},
+		{
+			name:      "replace",
+			transform: Replace([]string{"customer"}, "^(.)", "[$1]"),
+			want:      "id,customer,price,qty\n1,[a]lice,10,2\n2,[b]ob,25.5,1\n3,[a]lice,7,4\n4,[c]arol,100,1\n5,[b]ob,,3\n",
+		},
+		{
+			name:      "limit",
+			transform: Limit(2),
+			want:      "id,customer,price,qty\n1,alice,10,2\n2,bob,25.5,1\n",
+		},
+		{
+			name:      "offset",
+			transform: Offset(3),
+			want:      "id,customer,price,qty\n4,carol,100,1\n5,bob,,3\n",
+		},
+		{
+			name:      "tail",
+			transform: Tail(1),
+			want:      "id,customer,price,qty\n5,bob,,3\n",
+		},
+		{
+			name:      "group by",
+			transform: GroupBy([]string{"customer"}, map[string]AggFunc{"qty": Sum}),
+			want:      "customer,qty\nalice,6\nbob,4\ncarol,1\n",
+		},
+		{
+			name:      "group by without values",
+			transform: GroupBy([]string{"id"}, map[string]AggFunc{"price": Sum, "qty": Avg}),
+			want:      "id,price,qty\n1,10,2\n2,25.5,1\n3,7,4\n4,100,1\n5,,3\n",
+		},
+		{
+			name:      "top n per group",
+			transform: TopN(1, []string{"customer"}, "qty desc"),
+			want:      "id,customer,price,qty\n3,alice,7,4\n5,bob,,3\n4,carol,100,1\n",
+		},
+		{
+			name:      "mask",
+			transform: Mask(map[string]MaskMode{"customer": MaskFull, "price": MaskPartial}),
+			want:      "id,customer,price,qty\n1,****,****,2\n2,****,****,1\n3,****,****,4\n4,****,****,1\n5,****,,3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.transform(parseCSV(t, ordersCSV))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := toCSV(t, c); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Transform
+	}{
+		{"unknown column", SelectColumns([]string{"missing"})},
+		{"invalid filter", Filter("price >")},
+		{"filter of unknown column", Filter("missing > 1")},
+		{"sort by unknown column", SortBy("missing")},
+		{"group by unknown column", GroupBy([]string{"missing"}, nil)},
+		{"aggregated key column", GroupBy([]string{"customer"}, map[string]AggFunc{"Customer": Count})},
+		{"mask of unknown column", Mask(map[string]MaskMode{"missing": MaskFull})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.transform(parseCSV(t, ordersCSV)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
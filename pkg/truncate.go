@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+const ellipsis = "…"
+
+// Overflow selects how the TableRenderer shows values wider than
+// MaxColumnWidth.
+type Overflow int
+
+const (
+	// OverflowTruncate cuts values off with an ellipsis.
+	OverflowTruncate Overflow = iota
+	// OverflowWrap wraps values across multiple lines.
+	OverflowWrap
+	// OverflowFootnote cuts values off with an ellipsis and a reference
+	// to a footnote below the table, which holds the full value.
+	OverflowFootnote
+)
+
+// ParseOverflow returns the overflow mode with the given name, i.e.
+// "truncate", "wrap" or "footnote".
+func ParseOverflow(name string) (Overflow, error) {
+	switch strings.ToLower(name) {
+	case "truncate":
+		return OverflowTruncate, nil
+	case "wrap":
+		return OverflowWrap, nil
+	case "footnote":
+		return OverflowFootnote, nil
+	}
+
+	return OverflowTruncate, fmt.Errorf("unknown overflow mode %q, supported modes: truncate, wrap, footnote", name)
+}
+
+// truncate shortens every line of the value to the display width,
+// ending it with an ellipsis. The suffix is placed behind the ellipsis
+// and counts towards the width.
+func truncate(value string, width int, suffix string) (string, bool) {
+	lines := strings.Split(value, "\n")
+	truncated := false
+	for i, line := range lines {
+		if displayWidth(line) > width {
+			limit := width - displayWidth(suffix)
+			if limit < 1 {
+				limit = 1
+			}
+			lines[i] = runewidth.Truncate(line, limit, ellipsis) + suffix
+			truncated = true
+		}
+	}
+
+	return strings.Join(lines, "\n"), truncated
+}
+
+// truncateRows truncates the values of the rows in place to the width.
+// It returns the full values referenced by OverflowFootnote.
+func truncateRows(rows [][]string, width int, overflow Overflow) []string {
+	var notes []string
+	for _, row := range rows {
+		for j, value := range row {
+			if overflow != OverflowFootnote {
+				row[j], _ = truncate(value, width, "")
+				continue
+			}
+
+			if v, ok := truncate(value, width, fmt.Sprintf("[%d]", len(notes)+1)); ok {
+				row[j] = v
+				notes = append(notes, value)
+			}
+		}
+	}
+
+	return notes
+}
+
+// writeFootnotes writes the full values of truncated cells.
+func writeFootnotes(w io.Writer, notes []string) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("\n")
+	for i, note := range notes {
+		fmt.Fprintf(&b, "[%d] %s\n", i+1, note)
+	}
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
@@ -0,0 +1,181 @@
+package pkg
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts are the layouts tried when a value is parsed as a date.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	time.ANSIC,
+	"02 Jan 2006",
+	"Jan 2, 2006",
+}
+
+// parseNumber parses the value as a floating point number.
+func parseNumber(s string) (float64, bool) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return n, err == nil
+}
+
+// parseTime parses the value using the first matching layout of
+// timeLayouts.
+func parseTime(s string) (time.Time, bool) {
+	return parseTimeIn(s, time.UTC)
+}
+
+// parseTimeIn is parseTime, which takes values without time zone to be
+// in the location.
+func parseTimeIn(s string, loc *time.Location) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// ColumnType is the type inferred for the values of a column.
+type ColumnType int
+
+const (
+	// TypeString is the type of columns not matching any other type,
+	// including columns without values.
+	TypeString ColumnType = iota
+	// TypeInt is the type of columns of integers.
+	TypeInt
+	// TypeFloat is the type of columns of numbers, which are not all
+	// integers.
+	TypeFloat
+	// TypeBool is the type of columns of the values true and false,
+	// ignoring case.
+	TypeBool
+	// TypeTime is the type of columns of dates and times.
+	TypeTime
+)
+
+func (t ColumnType) String() string {
+	switch t {
+	case TypeInt:
+		return "int"
+	case TypeFloat:
+		return "float"
+	case TypeBool:
+		return "bool"
+	case TypeTime:
+		return "time"
+	}
+
+	return "string"
+}
+
+// Numeric reports whether the type is TypeInt or TypeFloat.
+func (t ColumnType) Numeric() bool {
+	return t == TypeInt || t == TypeFloat
+}
+
+// InferType returns the most specific type matching all values but the
+// empty ones and the null texts of DefaultNullStrings and query results.
+// Integers are preferred over other numbers, numbers over booleans and
+// booleans over dates.
+func InferType(values []string) ColumnType {
+	return inferType(values, nil)
+}
+
+// inferType is InferType ignoring the given null texts, see isNull.
+func inferType(values []string, nulls *NullStrings) ColumnType {
+	ints, floats, bools, times, seen := true, true, true, true, false
+	for _, v := range values {
+		if v == "" || isNull(v, nulls) {
+			continue
+		}
+
+		seen = true
+		if ints {
+			_, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			ints = err == nil
+		}
+		if floats {
+			_, floats = parseNumber(v)
+		}
+		if bools {
+			bools = strings.EqualFold(v, "true") || strings.EqualFold(v, "false")
+		}
+		if times {
+			_, times = parseTime(v)
+		}
+		if !floats && !bools && !times {
+			return TypeString
+		}
+	}
+
+	switch {
+	case !seen:
+		return TypeString
+	case ints:
+		return TypeInt
+	case floats:
+		return TypeFloat
+	case bools:
+		return TypeBool
+	case times:
+		return TypeTime
+	}
+
+	return TypeString
+}
+
+// Types returns the type of every column, inferred from its values by
+// InferType unless the types were inferred before by InferTypes.
+func (c Content) Types() []ColumnType {
+	return c.typesWith(nil)
+}
+
+// typesWith is Types ignoring the given null texts, see isNull.
+func (c Content) typesWith(nulls *NullStrings) []ColumnType {
+	if c.types != nil {
+		return c.types
+	}
+
+	types := make([]ColumnType, len(c.header))
+	for i := range types {
+		types[i] = inferType(c.column(i), nulls)
+	}
+
+	return types
+}
+
+// InferTypes returns the Content with the types of its columns
+// inferred, so that Types and the renderers do not inspect the values
+// again.
+func (c Content) InferTypes() Content {
+	c.types = c.Types()
+	return c
+}
+
+// numericColumns reports for every column of the Content whether all of
+// its non-empty values are numbers.
+func numericColumns(c Content) []bool {
+	types := c.Types()
+	out := make([]bool, len(types))
+	for i, t := range types {
+		out[i] = t.Numeric()
+	}
+
+	return out
+}
@@ -0,0 +1,37 @@
+package pkg
+
+import (
+	"github.com/mattn/go-runewidth"
+	"github.com/olekukonko/tablewriter"
+)
+
+// displayWidth returns the number of terminal columns the text takes.
+// Wide East Asian characters take two columns, combining marks none,
+// and grapheme clusters like emoji joined with zero width joiners or
+// with skin tone modifiers count as a single character. ANSI escape
+// sequences take no space. It measures like tablewriter, which pads
+// the cells of tables, so that borders stay aligned.
+func displayWidth(s string) int {
+	return tablewriter.DisplayWidth(s)
+}
+
+// graphemeWidth returns the width of a single grapheme cluster, i.e.
+// the width of its first rune which takes any space.
+func graphemeWidth(cluster string) int {
+	return runewidth.StringWidth(cluster)
+}
+
+// SetAmbiguousWidth sets the number of columns of characters whose
+// width depends on the font, e.g. "○" or "Ω", to 1 or 2. Terminals of
+// Chinese, Japanese and Korean locales typically print them twice as
+// wide. By default, the width is 2 in such locales, as detected from
+// the LC_ALL, LC_CTYPE and LANG environment variables, and 1 otherwise.
+// The RUNEWIDTH_EASTASIAN environment variable overrides the locale if
+// it is set to 0 or 1. It affects all tables rendered afterwards.
+func SetAmbiguousWidth(width int) {
+	// the condition is replaced rather than changed, as its lookup table,
+	// which tcell creates, would keep the previous widths
+	c := runewidth.NewCondition()
+	c.EastAsianWidth = width == 2
+	runewidth.DefaultCondition = c
+}
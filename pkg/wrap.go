@@ -0,0 +1,77 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/rivo/uniseg"
+)
+
+// wrapText wraps every line of the value at the display width. Words
+// wider than the width are kept whole, unless hard is set, in which
+// case they are broken up as well.
+func wrapText(value string, width int, hard bool) string {
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		if displayWidth(line) <= width {
+			lines = append(lines, line)
+			continue
+		}
+
+		wrapped, _ := tablewriter.WrapString(line, width)
+		for _, w := range wrapped {
+			if hard {
+				lines = append(lines, breakText(w, width)...)
+			} else {
+				lines = append(lines, w)
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// breakText splits the text into pieces no wider than the display
+// width. Grapheme clusters, e.g. emoji joined with zero width joiners,
+// are never broken up.
+func breakText(text string, width int) []string {
+	var pieces []string
+	var b strings.Builder
+	n := 0
+	g := uniseg.NewGraphemes(text)
+	for g.Next() {
+		w := graphemeWidth(g.Str())
+		if n+w > width && n > 0 {
+			pieces = append(pieces, strings.TrimRight(b.String(), " "))
+			b.Reset()
+			n = 0
+		}
+		b.WriteString(g.Str())
+		n += w
+	}
+
+	return append(pieces, b.String())
+}
+
+// colorLines colors every line of the value, so that the colors of
+// multi-line values are kept on all lines of the cell.
+func colorLines(value string, color []int) string {
+	if len(color) == 0 {
+		return value
+	}
+
+	codes := make([]string, len(color))
+	for i, code := range color {
+		codes[i] = strconv.Itoa(code)
+	}
+	sequence := strings.Join(codes, ";")
+
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		lines[i] = fmt.Sprintf("\x1b[%sm%s\x1b[0m", sequence, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,159 @@
+// Package xlsx reads and writes Excel workbooks. It is kept apart from
+// package pkg, so that only programs using workbooks depend on
+// excelize.
+// Importing it registers the "xlsx" format, e.g.:
+//
+//	import _ "github.com/frjufvjn/table-pretty/pkg/xlsx"
+package xlsx
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/olekukonko/tablewriter"
+	"github.com/xuri/excelize/v2"
+)
+
+func init() {
+	pkg.RegisterParser("xlsx", func() pkg.Parser { return &Parser{} })
+	pkg.RegisterRenderer("xlsx", func() pkg.Renderer { return &Renderer{} })
+	pkg.RegisterExtension(".xlsx", "xlsx")
+	pkg.RegisterMIMEType("application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx")
+}
+
+// Parser is a parser implementation that parses Excel workbooks.
+// The first row of the sheet is used as header.
+type Parser struct {
+	// Sheet is the name of the sheet to read, the first sheet of the
+	// workbook is used if it is unset.
+	Sheet string
+}
+
+// Parse converts the content of a reader to the Content representation.
+func (x *Parser) Parse(reader io.Reader) (pkg.Content, error) {
+	f, err := excelize.OpenReader(reader)
+	if err != nil {
+		return pkg.Content{}, err
+	}
+	defer f.Close()
+
+	sheet := x.Sheet
+	if sheet == "" {
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return pkg.Content{}, fmt.Errorf("workbook does not contain any sheets")
+		}
+		sheet = sheets[0]
+	}
+
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return pkg.Content{}, err
+	}
+
+	if len(rows) == 0 {
+		return pkg.Content{}, fmt.Errorf("sheet %q is empty", sheet)
+	}
+
+	// Trailing empty cells are omitted by excelize, so every row is
+	// padded to the width of the widest row.
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	for i, row := range rows {
+		if len(row) < width {
+			rows[i] = append(row, make([]string, width-len(row))...)
+		}
+	}
+
+	return pkg.NewContent(rows[0], rows[1:]), nil
+}
+
+// Renderer is a renderer implementation that writes an Excel
+// workbook with a frozen header row and columns sized to their content.
+type Renderer struct {
+	// Sheet is the name of the sheet to write, "Sheet1" is used if it
+	// is unset.
+	Sheet string
+}
+
+// maxColumnWidth is the widest column the Renderer produces.
+const maxColumnWidth = 80
+
+// Render writes the Content as an Excel workbook to the writer.
+func (x *Renderer) Render(c pkg.Content, w io.Writer) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := "Sheet1"
+	if x.Sheet != "" {
+		if err := f.SetSheetName(sheet, x.Sheet); err != nil {
+			return err
+		}
+		sheet = x.Sheet
+	}
+
+	header, rows := c.Header(), c.Rows()
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+
+	for i := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+
+		if err := f.SetSheetRow(sheet, cell, &rows[i]); err != nil {
+			return err
+		}
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	if err := f.SetRowStyle(sheet, 1, 1, bold); err != nil {
+		return err
+	}
+
+	err = f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, value := range header {
+		width := tablewriter.DisplayWidth(value)
+		for _, row := range rows {
+			if i < len(row) {
+				if w := tablewriter.DisplayWidth(row[i]); w > width {
+					width = w
+				}
+			}
+		}
+		if width > maxColumnWidth {
+			width = maxColumnWidth
+		}
+
+		col, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return err
+		}
+
+		if err := f.SetColWidth(sheet, col, col, float64(width+2)); err != nil {
+			return err
+		}
+	}
+
+	return f.Write(w)
+}
@@ -0,0 +1,118 @@
+package xlsx
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/frjufvjn/table-pretty/pkg"
+	"github.com/xuri/excelize/v2"
+)
+
+// toCSV renders the Content as csv, to compare it with the expected
+// document.
+func toCSV(t *testing.T, c pkg.Content) string {
+	t.Helper()
+
+	var b bytes.Buffer
+	if err := (&pkg.CSVRenderer{}).Render(c, &b); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.String()
+}
+
+// workbook renders the csv document as a workbook with the sheet.
+func workbook(t *testing.T, csv, sheet string) []byte {
+	t.Helper()
+
+	c, err := (&pkg.CSVParser{}).Parse(strings.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := (&Renderer{Sheet: sheet}).Render(c, &b); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.Bytes()
+}
+
+func TestParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser Parser
+		sheet  string
+		in     string
+	}{
+		{
+			name: "tricky values",
+			in:   "name,note\n\"a, b\",\"say \"\"hi\"\"\"\nc,\"two\nlines\"\nd,\n",
+		},
+		{
+			name:   "named sheet",
+			parser: Parser{Sheet: "fruits"},
+			sheet:  "fruits",
+			in:     "name,price\napple,1.5\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.parser
+			c, err := p.Parse(bytes.NewReader(workbook(t, tt.in, tt.sheet)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := toCSV(t, c); got != tt.in {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.in)
+			}
+		})
+	}
+}
+
+func TestParserErrors(t *testing.T) {
+	data := workbook(t, "name\napple\n", "")
+
+	if _, err := (&Parser{Sheet: "missing"}).Parse(bytes.NewReader(data)); err == nil {
+		t.Error("expected an error for a missing sheet")
+	}
+	if _, err := (&Parser{}).Parse(strings.NewReader("name\napple\n")); err == nil {
+		t.Error("expected an error for a document which is no workbook")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	p, _, err := pkg.DetectParser(bytes.NewReader(workbook(t, "name\napple\n", "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.(*Parser); !ok {
+		t.Errorf("detected %T, want *Parser", p)
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	data := workbook(t, "name,note\napple,"+strings.Repeat("x", 200)+"\n", "")
+
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	panes, err := f.GetPanes("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !panes.Freeze || panes.YSplit != 1 {
+		t.Errorf("the header row is not frozen: %+v", panes)
+	}
+
+	for col, want := range map[string]float64{"A": 7, "B": maxColumnWidth + 2} {
+		if got, err := f.GetColWidth("Sheet1", col); err != nil || got != want {
+			t.Errorf("column %s is %v wide, want %v", col, got, want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package pkg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// XMLParser is a parser implementation that parses XML documents. Every
+// row element is converted like a JSON object: child elements become
+// columns, repeated child elements become lists and elements with
+// children of their own become nested objects, which can be flattened.
+type XMLParser struct {
+	// RowElement is the name of the elements holding the rows. The
+	// children of the root element are used if it is unset.
+	RowElement string
+	// Attributes adds the attributes of the elements as columns named
+	// "@attribute".
+	Attributes bool
+	// Flatten turns nested elements into separate columns using
+	// dot-notation, e.g. "user.name".
+	Flatten bool
+	// MaxDepth limits how many levels are flattened, zero flattens all
+	// levels.
+	MaxDepth int
+	// Nested selects how repeated and nested elements which are not
+	// flattened are printed, as compact JSON by default.
+	Nested NestedMode
+	// Nulls are the texts of missing elements,
+	// DefaultNullStrings is used if it is unset.
+	Nulls *NullStrings
+}
+
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	children []*xmlNode
+	text     strings.Builder
+}
+
+// Parse converts the content of a reader to the Content representation.
+func (x *XMLParser) Parse(reader io.Reader) (Content, error) {
+	root, err := readXML(reader)
+	if err != nil {
+		return Content{}, err
+	}
+
+	var elements []*xmlNode
+	if x.RowElement == "" {
+		elements = root.children
+	} else {
+		elements = findXML(root, x.RowElement)
+		if len(elements) == 0 {
+			return Content{}, fmt.Errorf("no %q elements found", x.RowElement)
+		}
+	}
+
+	rows := make([]map[string]interface{}, len(elements))
+	for i, e := range elements {
+		row, ok := x.value(e).(map[string]interface{})
+		if !ok {
+			row = map[string]interface{}{e.name: strings.TrimSpace(e.text.String())}
+		}
+		rows[i] = row
+	}
+
+	if x.Flatten {
+		rows = flattenRows(rows, x.MaxDepth)
+	}
+
+	return mapsToContent(rows, x.Nulls, x.Nested), nil
+}
+
+// value converts an element to its text if it has neither children nor
+// (enabled) attributes, or to a map otherwise.
+func (x *XMLParser) value(n *xmlNode) interface{} {
+	text := strings.TrimSpace(n.text.String())
+	if len(n.children) == 0 && (!x.Attributes || len(n.attrs) == 0) {
+		return text
+	}
+
+	m := map[string]interface{}{}
+	if x.Attributes {
+		for _, attr := range n.attrs {
+			m["@"+attr.Name.Local] = attr.Value
+		}
+	}
+
+	for _, child := range n.children {
+		v := x.value(child)
+		switch existing := m[child.name].(type) {
+		case nil:
+			m[child.name] = v
+		case []interface{}:
+			m[child.name] = append(existing, v)
+		default:
+			m[child.name] = []interface{}{existing, v}
+		}
+	}
+
+	if text != "" {
+		m["#text"] = text
+	}
+
+	return m
+}
+
+// readXML reads the document into a tree and returns its root element.
+func readXML(r io.Reader) (*xmlNode, error) {
+	d := xml.NewDecoder(r)
+
+	var root *xmlNode
+	var stack []*xmlNode
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, xmlError(d, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: t.Name.Local, attrs: t.Attr}
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("document has more than one root element")
+				}
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("document has no root element")
+	}
+
+	return root, nil
+}
+
+// findXML returns the elements with the given name, without descending
+// into matching elements.
+func findXML(n *xmlNode, name string) []*xmlNode {
+	if n.name == name {
+		return []*xmlNode{n}
+	}
+
+	var out []*xmlNode
+	for _, child := range n.children {
+		out = append(out, findXML(child, name)...)
+	}
+
+	return out
+}
+
+// xmlError converts errors of encoding/xml into a ParseError.
+func xmlError(d *xml.Decoder, err error) error {
+	if syntaxErr, ok := err.(*xml.SyntaxError); ok {
+		return &ParseError{Line: syntaxErr.Line, Offset: d.InputOffset(), Err: syntaxErr}
+	}
+
+	return err
+}
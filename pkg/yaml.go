@@ -0,0 +1,72 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLParser is a parser implementation that parses YAML documents.
+// Every document of the stream may either be a list of mappings or a
+// single mapping, the rows of all documents are concatenated.
+type YAMLParser struct {
+	// Flatten turns nested mappings and sequences into separate columns
+	// using dot-notation, e.g. "user.name" or "tags.0".
+	Flatten bool
+	// MaxDepth limits how many levels are flattened, zero flattens all
+	// levels.
+	MaxDepth int
+	// Nested selects how sequences and mappings which are not flattened
+	// are printed, as compact JSON by default.
+	Nested NestedMode
+	// Nulls are the texts of null values, e.g. ~, and missing keys,
+	// DefaultNullStrings is used if it is unset.
+	Nulls *NullStrings
+}
+
+// Parse converts the content of a reader to the Content representation.
+func (y *YAMLParser) Parse(reader io.Reader) (Content, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return Content{}, err
+	}
+
+	d := yaml.NewDecoder(bytes.NewReader(data))
+
+	var rows []map[string]interface{}
+	for i := 1; ; i++ {
+		var doc interface{}
+		err := d.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Content{}, lineError(data, err)
+		}
+
+		switch v := doc.(type) {
+		case nil:
+			// empty document
+		case map[string]interface{}:
+			rows = append(rows, v)
+		case []interface{}:
+			for j, element := range v {
+				row, ok := element.(map[string]interface{})
+				if !ok {
+					return Content{}, fmt.Errorf("yaml document %d: element %d is not a mapping", i, j+1)
+				}
+				rows = append(rows, row)
+			}
+		default:
+			return Content{}, fmt.Errorf("yaml document %d is neither a list nor a mapping", i)
+		}
+	}
+
+	if y.Flatten {
+		rows = flattenRows(rows, y.MaxDepth)
+	}
+
+	return mapsToContent(rows, y.Nulls, y.Nested), nil
+}